@@ -0,0 +1,138 @@
+package phonedata
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Result 是批量查询中一个号码的结果，Record 和 Err 有且只有一个不为 nil。
+type Result struct {
+	Number string
+	Record *PhoneRecord
+	Err    error // 查询失败时为 *LookupError；ctx 被取消时为 ctx.Err()
+}
+
+// BatchOption 是批量查询的可选配置。
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	workers int
+}
+
+func newBatchOptions(opts []BatchOption) batchOptions {
+	o := batchOptions{workers: runtime.GOMAXPROCS(0)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithWorkers 指定批量查询并发的 goroutine 数，默认为 GOMAXPROCS。
+func WithWorkers(n int) BatchOption {
+	return func(o *batchOptions) {
+		if n > 0 {
+			o.workers = n
+		}
+	}
+}
+
+// FindMany 并发查询 numbers，按输入顺序返回每个号码的结果。
+// 整个批次使用同一份数据，不受期间 Reload 的影响。ctx 被取消后，尚未查询的号码的 Err 为 ctx.Err()。
+func (db *DB) FindMany(ctx context.Context, numbers []string, opts ...BatchOption) []Result {
+	s := db.snapshot()
+	o := newBatchOptions(opts)
+	results := make([]Result, len(numbers))
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for w := 0; w < o.workers && w < len(numbers); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(numbers) {
+					return
+				}
+				results[i] = s.result(ctx, numbers[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// FindStream 并发查询从 numbers 读到的号码，按读入顺序把结果写入返回的 channel。
+// numbers 被关闭或 ctx 被取消后，返回的 channel 会被关闭。ctx 被取消后不再读取 numbers，
+// 已经读到的号码仍各有一个 Err 为 ctx.Err() 的结果，调用方需要一直读到 channel 关闭。
+func (db *DB) FindStream(ctx context.Context, numbers <-chan string, opts ...BatchOption) <-chan Result {
+	s := db.snapshot()
+	o := newBatchOptions(opts)
+
+	type job struct {
+		number string
+		result chan Result
+	}
+	jobs := make(chan job)
+	pending := make(chan chan Result, o.workers) // 按读入顺序排队等待输出的结果
+	out := make(chan Result)
+
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for {
+			var number string
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case number, ok = <-numbers:
+			}
+			if !ok {
+				return
+			}
+			// 读到的号码一定会有结果：ctx 被取消后 worker 返回 ctx.Err()
+			result := make(chan Result, 1)
+			pending <- result
+			jobs <- job{number: number, result: result}
+		}
+	}()
+
+	for w := 0; w < o.workers; w++ {
+		go func() {
+			for j := range jobs {
+				j.result <- s.result(ctx, j.number)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(out)
+		for result := range pending {
+			out <- <-result
+		}
+	}()
+	return out
+}
+
+func (s *snapshot) result(ctx context.Context, number string) Result {
+	if err := ctx.Err(); err != nil {
+		return Result{Number: number, Err: err}
+	}
+	pr, err := s.find(number)
+	return Result{Number: number, Record: pr, Err: err}
+}
+
+// FindMany 在默认 DB 中批量查询，见 (*DB).FindMany。
+func FindMany(ctx context.Context, numbers []string, opts ...BatchOption) []Result {
+	db, err := Default()
+	if err != nil {
+		results := make([]Result, len(numbers))
+		for i, number := range numbers {
+			results[i] = Result{Number: number, Err: err}
+		}
+		return results
+	}
+	return db.FindMany(ctx, numbers, opts...)
+}
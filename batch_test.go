@@ -0,0 +1,104 @@
+package phonedata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func batchNumbers() []string {
+	var numbers []string
+	for prefix := 1300000; prefix < 2000000; prefix += 1009 {
+		numbers = append(numbers, fmt.Sprintf("%d1234", prefix))
+	}
+	return append(numbers, "1300", "afsd32323")
+}
+
+func TestFindMany(t *testing.T) {
+	numbers := batchNumbers()
+	results := FindMany(context.Background(), numbers, WithWorkers(4))
+	if len(results) != len(numbers) {
+		t.Fatal("验证失败", len(results))
+	}
+	for i, r := range results {
+		pr, err := Find(numbers[i])
+		if r.Number != numbers[i] || fmt.Sprint(r.Record) != fmt.Sprint(pr) || fmt.Sprint(r.Err) != fmt.Sprint(err) {
+			t.Fatal(numbers[i], "验证失败", r)
+		}
+	}
+	var lookupErr *LookupError
+	if !errors.As(results[len(results)-1].Err, &lookupErr) {
+		t.Fatal("错误的结果", results[len(results)-1])
+	}
+}
+
+func TestFindManyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, r := range FindMany(ctx, batchNumbers()) {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Fatal("错误的结果", r)
+		}
+	}
+}
+
+func TestFindStream(t *testing.T) {
+	db, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	numbers := batchNumbers()
+	in := make(chan string)
+	go func() {
+		for _, number := range numbers {
+			in <- number
+		}
+		close(in)
+	}()
+	i := 0
+	for r := range db.FindStream(context.Background(), in, WithWorkers(3)) {
+		pr, err := db.Find(numbers[i])
+		if r.Number != numbers[i] || fmt.Sprint(r.Record) != fmt.Sprint(pr) || fmt.Sprint(r.Err) != fmt.Sprint(err) {
+			t.Fatal(numbers[i], "验证失败", r)
+		}
+		i++
+	}
+	if i != len(numbers) {
+		t.Fatal("验证失败", i)
+	}
+}
+
+func TestFindStreamCanceled(t *testing.T) {
+	db, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string)
+	out := db.FindStream(ctx, in)
+	in <- "18957509123"
+	if r := <-out; r.Err != nil {
+		t.Fatal(r.Err)
+	}
+	cancel()
+	for range out {
+	}
+
+	// 取消后已经读入的号码都有结果，没有读入的留在 channel 中
+	in = make(chan string, 100)
+	for i := 0; i < cap(in); i++ {
+		in <- fmt.Sprint(18957500000 + i)
+	}
+	close(in)
+	count := 0
+	for r := range db.FindStream(ctx, in, WithWorkers(2)) {
+		if r.Number != fmt.Sprint(18957500000+count) || !errors.Is(r.Err, context.Canceled) {
+			t.Fatal("错误的结果", r)
+		}
+		count++
+	}
+	if count+len(in) != cap(in) {
+		t.Fatal("验证失败", count, len(in))
+	}
+}
@@ -0,0 +1,14 @@
+package phonedata
+
+import (
+	"github.com/xluohome/phonedata/carrier"
+)
+
+// Carrier 是号码所属的运营商，见 carrier.Carrier。
+type Carrier = carrier.Carrier
+
+// CarrierOf 在内置的运营商注册表中查询卡类型 card_type 对应的运营商。
+// 未知的卡类型返回只有 ID 和名称 "未知电信运营商" 的 Carrier，其 Known 为 false。
+func CarrierOf(card_type byte) Carrier {
+	return carrier.Default().Of(card_type)
+}
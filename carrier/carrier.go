@@ -0,0 +1,221 @@
+// Package carrier 是运营商（卡类型）的注册表，phonedata 和 phonedatatool 共用。
+//
+// Registry 创建后不能修改，扩展时用 With 或 LoadFile 得到新的 Registry。
+package carrier
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 内置的卡类型，与 phone.dat 索引区中的卡类型字节一致。
+const (
+	CMCC   byte = iota + 0x01 //中国移动
+	CUCC                      //中国联通
+	CTCC                      //中国电信
+	CTCC_v                    //电信虚拟运营商
+	CUCC_v                    //联通虚拟运营商
+	CMCC_v                    //移动虚拟运营商
+	CBN                       //中国广电
+	CBN_v                     //广电虚拟运营商
+)
+
+const (
+	UnknownName        = "未知电信运营商"
+	UnknownEnglishName = "Unknown"
+)
+
+// Carrier 是号码所属的运营商，由索引区每条记录的卡类型字节解码得到。
+type Carrier struct {
+	ID          byte   // 卡类型，即索引区中的原始字节，如 CMCC
+	Code        string // 稳定的运营商代码：CMCC、CUCC、CTCC、CBN，虚拟运营商加 "_V" 后缀，未知卡类型为空
+	MVNO        bool   // 是否虚拟运营商
+	Network     string // 实际使用的网络的运营商代码，基础运营商与 Code 相同
+	Name        string // 中文名，如 "中国移动"
+	EnglishName string // 英文名，如 "China Mobile"
+}
+
+func (c Carrier) String() string {
+	return c.Name
+}
+
+// Known 判断卡类型是否是已知的运营商。
+func (c Carrier) Known() bool {
+	return c.Code != ""
+}
+
+// Registry 是卡类型到运营商的映射，创建后只读，可以在多个 goroutine 中共用。
+type Registry struct {
+	byID   map[byte]Carrier
+	byCode map[string]Carrier
+	ids    []byte
+}
+
+var defaultRegistry = mustNew(
+	Carrier{ID: CMCC, Code: "CMCC", Name: "中国移动", EnglishName: "China Mobile"},
+	Carrier{ID: CUCC, Code: "CUCC", Name: "中国联通", EnglishName: "China Unicom"},
+	Carrier{ID: CTCC, Code: "CTCC", Name: "中国电信", EnglishName: "China Telecom"},
+	Carrier{ID: CTCC_v, Code: "CTCC_V", Network: "CTCC", Name: "中国电信虚拟运营商", EnglishName: "China Telecom MVNO"},
+	Carrier{ID: CUCC_v, Code: "CUCC_V", Network: "CUCC", Name: "中国联通虚拟运营商", EnglishName: "China Unicom MVNO"},
+	Carrier{ID: CMCC_v, Code: "CMCC_V", Network: "CMCC", Name: "中国移动虚拟运营商", EnglishName: "China Mobile MVNO"},
+	Carrier{ID: CBN, Code: "CBN", Name: "中国广电", EnglishName: "China Broadcast Network"},
+	Carrier{ID: CBN_v, Code: "CBN_V", Network: "CBN", Name: "中国广电虚拟运营商", EnglishName: "China Broadcast Network MVNO"},
+)
+
+// Default 返回内置的注册表。
+func Default() *Registry {
+	return defaultRegistry
+}
+
+func mustNew(carriers ...Carrier) *Registry {
+	r, err := New(carriers...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// New 用 carriers 创建注册表。Network 为空时表示基础运营商，取 Code 的值；
+// Network 与 Code 不同时为虚拟运营商，MVNO 由此得出，不需要填写。
+// ID 不能为 0，ID 和 Code 不能重复，Network 必须是注册表中某个运营商的 Code。
+func New(carriers ...Carrier) (*Registry, error) {
+	r := &Registry{
+		byID:   make(map[byte]Carrier, len(carriers)),
+		byCode: make(map[string]Carrier, len(carriers)),
+	}
+	for _, c := range carriers {
+		if c.ID == 0 {
+			return nil, fmt.Errorf("carrier %v: card type id 0 is reserved", c.Code)
+		}
+		if c.Code == "" || strings.ContainsAny(c.Code, "| \t\n") {
+			return nil, fmt.Errorf("carrier %v: invalid code %q", c.ID, c.Code)
+		}
+		if _, err := strconv.Atoi(c.Code); err == nil {
+			return nil, fmt.Errorf("carrier %v: code %q must not be a number", c.ID, c.Code)
+		}
+		c.Code = strings.ToUpper(c.Code)
+		c.Network = strings.ToUpper(c.Network)
+		if c.Network == "" {
+			c.Network = c.Code
+		}
+		c.MVNO = c.Network != c.Code
+		if _, ok := r.byID[c.ID]; ok {
+			return nil, fmt.Errorf("carrier %v: duplicate card type id %v", c.Code, c.ID)
+		}
+		if _, ok := r.byCode[c.Code]; ok {
+			return nil, fmt.Errorf("carrier %v: duplicate code %v", c.ID, c.Code)
+		}
+		r.byID[c.ID] = c
+		r.byCode[c.Code] = c
+		r.ids = append(r.ids, c.ID)
+	}
+	sort.Slice(r.ids, func(i, j int) bool { return r.ids[i] < r.ids[j] })
+	for _, id := range r.ids {
+		if c := r.byID[id]; r.byCode[c.Network].Code == "" {
+			return nil, fmt.Errorf("carrier %v: unknown network %v", c.Code, c.Network)
+		}
+	}
+	return r, nil
+}
+
+// With 返回在 r 的基础上增加了 carriers 的新注册表，r 本身不变。
+func (r *Registry) With(carriers ...Carrier) (*Registry, error) {
+	return New(append(r.All(), carriers...)...)
+}
+
+// LoadFile 从文件 path 读取运营商定义（格式见 Read），返回在 r 的基础上增加了这些运营商的新注册表。
+func (r *Registry) LoadFile(path string) (*Registry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	carriers, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return r.With(carriers...)
+}
+
+// Lookup 返回卡类型 id 对应的运营商，ok 表示是否已注册。
+func (r *Registry) Lookup(id byte) (c Carrier, ok bool) {
+	c, ok = r.byID[id]
+	return c, ok
+}
+
+// Of 返回卡类型 id 对应的运营商。
+// 未注册的卡类型返回只有 ID 和名称 UnknownName 的 Carrier，其 Known 为 false。
+func (r *Registry) Of(id byte) Carrier {
+	if c, ok := r.byID[id]; ok {
+		return c
+	}
+	return Carrier{ID: id, Name: UnknownName, EnglishName: UnknownEnglishName}
+}
+
+// ByCode 按运营商代码查找，不区分大小写。
+func (r *Registry) ByCode(code string) (c Carrier, ok bool) {
+	c, ok = r.byCode[strings.ToUpper(code)]
+	return c, ok
+}
+
+// Parse 把卡类型的文本表示解析成运营商。s 可以是 0~255 的数字，也可以是运营商代码，如 "CMCC"。
+// 数字不要求已注册，代码必须已注册。
+func (r *Registry) Parse(s string) (Carrier, error) {
+	if v, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return r.Of(byte(v)), nil
+	} else if c, ok := r.ByCode(s); ok {
+		return c, nil
+	} else {
+		return Carrier{}, fmt.Errorf("invalid card type %q: neither an id in 0~255 nor a known carrier code", s)
+	}
+}
+
+// All 返回全部已注册的运营商，按 ID 升序。
+func (r *Registry) All() []Carrier {
+	carriers := make([]Carrier, 0, len(r.ids))
+	for _, id := range r.ids {
+		carriers = append(carriers, r.byID[id])
+	}
+	return carriers
+}
+
+// Read 读取运营商定义。每行一个运营商，由竖线分隔成 5 段：
+//
+//	ID|代码|网络|中文名|英文名
+//
+// 例如 "9|XYZ_V|CMCC|某虚拟运营商|XYZ Mobile"。网络为空表示基础运营商。
+// 空行和以 '#' 开头的行被忽略。
+func Read(reader io.Reader) ([]Carrier, error) {
+	var carriers []Carrier
+	scanner := bufio.NewScanner(reader)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words := strings.Split(line, "|")
+		if len(words) != 5 {
+			return nil, fmt.Errorf("line %v: expect words len is 5, got %v, %v", lineNo, len(words), words)
+		}
+		id, err := strconv.ParseUint(words[0], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: invalid card type id %v: %v", lineNo, words[0], err)
+		}
+		carriers = append(carriers, Carrier{
+			ID:          byte(id),
+			Code:        words[1],
+			Network:     words[2],
+			Name:        words[3],
+			EnglishName: words[4],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return carriers, nil
+}
@@ -0,0 +1,93 @@
+package carrier
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	r := Default()
+	if len(r.All()) != 8 {
+		t.Fatal("验证失败", r.All())
+	}
+	for i, c := range r.All() {
+		if c.ID != byte(i+1) || !c.Known() || c.MVNO != (c.Network != c.Code) {
+			t.Fatal("验证失败", c)
+		}
+	}
+	if c, ok := r.ByCode("cbn_v"); !ok || c.ID != CBN_v || !c.MVNO || c.Network != "CBN" {
+		t.Fatal("验证失败", c)
+	}
+	if c := r.Of(0xff); c.Known() || c.ID != 0xff || c.Name != UnknownName {
+		t.Fatal("验证失败", c)
+	}
+	if _, ok := r.Lookup(0); ok {
+		t.Fatal("验证失败")
+	}
+}
+
+func TestParse(t *testing.T) {
+	for s, id := range map[string]byte{"1": CMCC, "CMCC": CMCC, "ctcc_v": CTCC_v, "7": CBN, "200": 200} {
+		if c, err := Default().Parse(s); err != nil || c.ID != id {
+			t.Fatal(s, "验证失败", c, err)
+		}
+	}
+	for _, s := range []string{"", "256", "-1", "XYZ"} {
+		if _, err := Default().Parse(s); err == nil {
+			t.Fatal(s, "应该返回错误")
+		}
+	}
+}
+
+func TestWith(t *testing.T) {
+	r, err := Default().With(Carrier{ID: 9, Code: "xyz_v", Network: "cmcc", Name: "某虚拟运营商", EnglishName: "XYZ Mobile"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := r.Of(9); c.Code != "XYZ_V" || !c.MVNO || c.Network != "CMCC" {
+		t.Fatal("验证失败", c)
+	}
+	if _, ok := Default().Lookup(9); ok {
+		t.Fatal("Default 不应被修改")
+	}
+
+	for _, c := range []Carrier{
+		{ID: 0, Code: "ZERO"},
+		{ID: CMCC, Code: "DUP"},
+		{ID: 9, Code: "CMCC"},
+		{ID: 9, Code: ""},
+		{ID: 9, Code: "10"},
+		{ID: 9, Code: "XYZ", Network: "NONE"},
+	} {
+		if _, err := Default().With(c); err == nil {
+			t.Fatal(c, "应该返回错误")
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "carriers.txt")
+	content := "# 自定义运营商\n\n9|XYZ_V|CMCC|某虚拟运营商|XYZ Mobile\n10|NEW||新运营商|New Carrier\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Default().LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := r.Of(10); c.Code != "NEW" || c.MVNO || c.Network != "NEW" || c.Name != "新运营商" {
+		t.Fatal("验证失败", c)
+	}
+	if len(r.All()) != 10 {
+		t.Fatal("验证失败", r.All())
+	}
+
+	if _, err := Read(strings.NewReader("9|XYZ|CMCC|名称\n")); err == nil {
+		t.Fatal("应该返回错误")
+	}
+	if _, err := Read(strings.NewReader("x|XYZ||名称|Name\n")); err == nil {
+		t.Fatal("应该返回错误")
+	}
+}
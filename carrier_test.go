@@ -0,0 +1,77 @@
+package phonedata
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	"github.com/xluohome/phonedata/carrier"
+)
+
+func TestCarrier(t *testing.T) {
+	pr, err := Find("13004771234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := pr.Carrier; c.ID != CUCC || c.Code != "CUCC" || c.MVNO || c.Network != "CUCC" || c.Name != pr.CardType {
+		t.Fatal("验证失败", c)
+	}
+
+	for card_type, name := range CardTypemap {
+		c := CarrierOf(card_type)
+		if !c.Known() || c.ID != card_type || c.Name != name || c.EnglishName == "" {
+			t.Fatal("验证失败", card_type, c)
+		}
+		if c.MVNO != (c.Code != c.Network) {
+			t.Fatal("验证失败", card_type, c)
+		}
+	}
+	if c := CarrierOf(CMCC_v); !c.MVNO || c.Code != "CMCC_V" || c.Network != "CMCC" {
+		t.Fatal("验证失败", c)
+	}
+	if c := CarrierOf(0xff); c.Known() || c.ID != 0xff || c.Name != "未知电信运营商" {
+		t.Fatal("验证失败", c)
+	}
+
+	pr, err = Find("057512345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Carrier.Known() {
+		t.Fatal("固定电话不应有运营商", pr.Carrier)
+	}
+}
+
+func TestWithCarriers(t *testing.T) {
+	content, err := ioutil.ReadFile(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 把 1300000 号段的卡类型改为自定义的 9
+	for i := binary.LittleEndian.Uint32(content[INT_LEN:]); int(i)+PHONE_INDEX_LENGTH <= len(content); i += PHONE_INDEX_LENGTH {
+		if binary.LittleEndian.Uint32(content[i:]) == 1300000 {
+			content[i+PHONE_INDEX_LENGTH-1] = 9
+			break
+		}
+	}
+
+	db, err := Load(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr, err := db.Find("13000001234"); err != nil || pr.Carrier.Known() || pr.CardType != "未知电信运营商" {
+		t.Fatal("验证失败", pr, err)
+	}
+
+	carriers, err := carrier.Default().With(carrier.Carrier{ID: 9, Code: "XYZ_V", Network: "CUCC", Name: "某虚拟运营商"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err = Load(content, WithCarriers(carriers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr, err := db.Find("13000001234"); err != nil || pr.Carrier.Code != "XYZ_V" || pr.CardType != "某虚拟运营商" {
+		t.Fatal("验证失败", pr, err)
+	}
+}
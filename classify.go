@@ -0,0 +1,120 @@
+package phonedata
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/xluohome/phonedata/carrier"
+	"github.com/xluohome/phonedata/reader"
+)
+
+// Classification 是 Classify 的结果。
+type Classification struct {
+	Type       NumberType
+	Normalized string         // 整理后的号码，国外号码为 + 开头的国际格式，无效号码为空
+	Record     *PhoneRecord   // 手机号码、固定电话、物联网号码的查询结果，查不到时为 nil
+	Service    *ServiceRecord // 服务号码在服务号码表中的记录，不在表中时为 nil
+	Err        error          // 查询归属地失败的原因
+}
+
+// serviceNumberPrefixes 是短号码、服务号码的前缀及对应的号码长度。
+var serviceNumberPrefixes = []struct {
+	prefix  string
+	lengths []int
+}{
+	{"11", []int{3}},       // 110、114、119 等
+	{"12", []int{3, 5}},    // 120、122，以及 12345、12315 等政务服务号码
+	{"100", []int{5}},      // 10086、10010、10000 等运营商服务号码
+	{"101", []int{5}},      // 10101 等
+	{"95", []int{5, 6, 8}}, // 95588、95598 等全国统一服务号码
+	{"96", []int{5, 6}},    // 地方服务号码
+	{"400", []int{10}},     // 400 企业服务号码
+	{"800", []int{10}},     // 800 免费服务号码
+}
+
+func isServiceNumber(national string) bool {
+	for _, p := range serviceNumberPrefixes {
+		if !strings.HasPrefix(national, p.prefix) {
+			continue
+		}
+		for _, n := range p.lengths {
+			if len(national) == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isMobileNumber(national string) bool {
+	return len(national) == 11 && national[0] == '1' && national[1] >= '3'
+}
+
+// isIoTNumber 判断已知号段的 13 位物联网号码，号段表见 IoTCardType。
+func isIoTNumber(national string) bool {
+	_, ok := IoTCardType(national)
+	return ok
+}
+
+// Classify 判断号码的类型：手机号码、固定电话、服务号码、物联网号码、国外号码或无效号码。
+// 手机号码和固定电话会接着查询归属地，物联网号码会根据号段确定运营商，结果放在 Record 中。
+func (db *DB) Classify(number string) Classification {
+	return classify(db.snapshot(), number)
+}
+
+// classify 判断号码类型，s 为 nil 时不查询归属地。
+func classify(s *snapshot, number string) Classification {
+	national, err := Normalize(number)
+	if err != nil {
+		if errors.Is(err, ErrInternational) {
+			digits, _, _ := normalizeDigits(number)
+			return Classification{Type: International, Normalized: "+" + strings.TrimPrefix(digits, "00")}
+		}
+		return Classification{Type: Invalid, Err: err}
+	}
+
+	c := Classification{Normalized: national}
+	switch {
+	case isServiceNumber(national) || (s != nil && s.services[national] != nil):
+		c.Type = Service
+		if s != nil {
+			c.Service = s.services[national]
+		}
+	case isIoTNumber(national):
+		c.Type = IoT
+		registry := carrier.Default()
+		if s != nil {
+			registry = s.carriers
+		}
+		c.Record, c.Err = reader.FindIoT(number, national, registry)
+	case isMobileNumber(national):
+		c.Type = Mobile
+		if s != nil {
+			c.Record, c.Err = s.find(national)
+		}
+	case national[0] == '0':
+		if _, _, err := ParseLandline(national); err != nil {
+			return Classification{Type: Invalid, Err: err}
+		}
+		c.Type = FixedLine
+		if s != nil {
+			c.Record, c.Err = s.findLandline(national)
+		}
+	default:
+		return Classification{Type: Invalid, Err: lookupError(number, ErrInvalidNumber)}
+	}
+	return c
+}
+
+// Classify 使用默认 DB 判断号码类型，见 (*DB).Classify。默认 DB 加载失败时不查询归属地。
+func Classify(number string) Classification {
+	db, err := Default()
+	if err != nil {
+		c := classify(nil, number)
+		if c.Err == nil {
+			c.Err = err
+		}
+		return c
+	}
+	return db.Classify(number)
+}
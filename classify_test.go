@@ -0,0 +1,47 @@
+package phonedata
+
+import (
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	for number, expected := range map[string]struct {
+		t          NumberType
+		normalized string
+	}{
+		"+86 189-5750-9123": {Mobile, "18957509123"},
+		"0571-88888888":     {FixedLine, "057188888888"},
+		"10086":             {Service, "10086"},
+		"95588":             {Service, "95588"},
+		"12345":             {Service, "12345"},
+		"110":               {Service, "110"},
+		"400-123-4567":      {Service, "4001234567"},
+		"800 810 8888":      {Service, "8008108888"},
+		"1440123456789":     {IoT, "1440123456789"},
+		"1064812345678":     {IoT, "1064812345678"},
+		"1064712345678":     {Invalid, ""},
+		"+1 650 253 0000":   {International, "+16502530000"},
+		"0044 20 7946 0000": {International, "+442079460000"},
+		"1300":              {Invalid, ""},
+		"afsd32323":         {Invalid, ""},
+		"12345678":          {Invalid, ""},
+		"0571-123":          {Invalid, ""},
+	} {
+		c := Classify(number)
+		if c.Type != expected.t || c.Normalized != expected.normalized {
+			t.Fatal(number, "验证失败", c.Type, c.Normalized)
+		}
+	}
+
+	c := Classify("18957509123")
+	if c.Err != nil || c.Record == nil || c.Record.City != "绍兴" {
+		t.Fatal("验证失败", c)
+	}
+	c = Classify("0571-88888888")
+	if c.Err != nil || c.Record == nil || c.Record.City != "杭州" || c.Record.Type != FixedLine {
+		t.Fatal("验证失败", c)
+	}
+	if c = Classify("10086"); c.Record != nil || c.Err != nil {
+		t.Fatal("验证失败", c)
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/xluohome/phonedata"
+	"github.com/xluohome/phonedata/embedded"
 )
 
 func main() {
@@ -13,7 +13,7 @@ func main() {
 		fmt.Print("请输入手机号")
 		return
 	}
-	pr, err := phonedata.Find(os.Args[1])
+	pr, err := embedded.Find(os.Args[1])
 	if err != nil {
 		fmt.Printf("%s", err)
 		return
@@ -1,18 +1,32 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
+	"github.com/xluohome/phonedata"
+	"github.com/xluohome/phonedata/carrier"
+	"github.com/xluohome/phonedata/phonedatatool"
+	"github.com/xluohome/phonedata/phonedatatool/embedgen"
 	"github.com/xluohome/phonedata/phonedatatool/pack"
 	"github.com/xluohome/phonedata/phonedatatool/util"
+	"github.com/xluohome/phonedata/reader"
+	"io"
 	"os"
 	"path"
+	"strings"
 )
 
 // 这里编译出来的可执行程序具备打包、查询、解包三个功能。
 // ./phonedatatool -unpack -i phone.dat -o tmp
 // ./phonedatatool -pack -i tmp -o phone.dat
 // ./phonedatatool -query -i phone.dat -number 13000001234
+// ./phonedatatool -query -i phone.dat -number 13000001234,18957509123 15800000000
+// ./phonedatatool -query -i phone.dat < numbers.txt
+// ./phonedatatool -embed -i phone.dat -o embedded -package embedded
+// ./phonedatatool -reverse -i phone.dat -province 浙江 -city 绍兴 -carrier 3
+// ./phonedatatool -roundtrip-check -i phone.dat
+// 打包、查询、反查时可以用 -carriers 指定额外的运营商定义文件。
 
 const (
 	Name     = "phonedatatool"
@@ -25,6 +39,7 @@ const (
 	VersionFileName = "version.txt"
 	RecordFileName  = "record.txt"
 	IndexFileName   = "index.txt"
+	ServiceFileName = "service.txt"
 )
 
 func main() {
@@ -33,9 +48,17 @@ func main() {
 	unpackFlag := flag.Bool("unpack", false, "Unpack phone data to plain text")
 	packFlag := flag.Bool("pack", false, "Pack plain text to phone data")
 	queryFlag := flag.Bool("query", false, "Query number from phone data")
+	embedFlag := flag.Bool("embed", false, "Generate a Go package embedding phone data")
 	source := flag.String("i", "", "Source of operation")
 	destination := flag.String("o", "", "Destination of operation")
-	number := flag.String("number", "", "Number to query")
+	number := flag.String("number", "", "Numbers to query, separated by commas")
+	packageName := flag.String("package", "embedded", "Package name of generated Go package")
+	reverseFlag := flag.Bool("reverse", false, "List number prefixes of a province or city")
+	province := flag.String("province", "", "Province name to reverse lookup")
+	city := flag.String("city", "", "City name to reverse lookup")
+	cardType := flag.String("carrier", "", "Card type ID or carrier code to filter reverse lookup")
+	carriersFile := flag.String("carriers", "", "File of extra carrier definitions")
+	roundTripCheckFlag := flag.Bool("roundtrip-check", false, "Check that unpacking and packing phone data reproduces the same bytes")
 	flag.Parse()
 	if *showVersionFlag {
 		fmt.Println("Version:", FullName)
@@ -45,6 +68,15 @@ func main() {
 		showHelp()
 		return
 	}
+	carriers := carrier.Default()
+	if *carriersFile != "" {
+		if r, err := carriers.LoadFile(*carriersFile); err != nil {
+			fmt.Println("ERROR! Load carriers failed.", err)
+			return
+		} else {
+			carriers = r
+		}
+	}
 	if *unpackFlag {
 		if source == nil {
 			fmt.Println("ERROR! No source")
@@ -71,7 +103,7 @@ func main() {
 			fmt.Println("ERROR! No destination")
 			return
 		}
-		if err := Pack(*source, *destination); err != nil {
+		if err := Pack(*source, *destination, carriers); err != nil {
 			fmt.Println("ERROR! Pack failed.", err)
 			return
 		} else {
@@ -84,11 +116,21 @@ func main() {
 			fmt.Println("ERROR! No source")
 			return
 		}
-		if number == nil {
+		numbers := queryNumbers(*number, flag.Args())
+		if len(numbers) == 0 {
+			// 没有在命令行指定号码时从标准输入读取，每行一个
+			if v, err := readNumbers(os.Stdin); err != nil {
+				fmt.Println("ERROR! Read numbers failed.", err)
+				return
+			} else {
+				numbers = v
+			}
+		}
+		if len(numbers) == 0 {
 			fmt.Println("ERROR! No number to query")
 			return
 		}
-		if err := QueryNumber(*source, *number); err != nil {
+		if err := QueryNumbers(*source, numbers, carriers); err != nil {
 			fmt.Println("ERROR! Query failed.", err)
 			return
 		} else {
@@ -96,6 +138,53 @@ func main() {
 			return
 		}
 	}
+	if *embedFlag {
+		if source == nil {
+			fmt.Println("ERROR! No source")
+			return
+		}
+		if destination == nil {
+			fmt.Println("ERROR! No destination")
+			return
+		}
+		if err := Embed(*source, *destination, *packageName); err != nil {
+			fmt.Println("ERROR! Embed failed.", err)
+			return
+		} else {
+			fmt.Println("Embed completed.")
+			return
+		}
+	}
+	if *reverseFlag {
+		if source == nil {
+			fmt.Println("ERROR! No source")
+			return
+		}
+		if *province == "" && *city == "" {
+			fmt.Println("ERROR! No province or city")
+			return
+		}
+		if err := Reverse(*source, *province, *city, *cardType, carriers); err != nil {
+			fmt.Println("ERROR! Reverse failed.", err)
+			return
+		} else {
+			fmt.Println("Reverse completed.")
+			return
+		}
+	}
+	if *roundTripCheckFlag {
+		if source == nil {
+			fmt.Println("ERROR! No source")
+			return
+		}
+		if err := RoundTripCheck(*source); err != nil {
+			fmt.Println("ERROR! Round-trip check failed.", err)
+			os.Exit(1)
+		} else {
+			fmt.Println("Round-trip check passed.")
+			return
+		}
+	}
 	fmt.Println("Did nothing.")
 	showHelp()
 	return
@@ -105,10 +194,16 @@ func showHelp() {
 	fmt.Println("<< HELP >>")
 	fmt.Println("./phonedatatool -unpack -i phone.dat -o tmp")
 	fmt.Println("./phonedatatool -pack -i tmp -o phone.dat")
-	fmt.Println("./phonedatatool -query -i phone.dat -n 13000001234")
+	fmt.Println("./phonedatatool -query -i phone.dat -number 13000001234")
+	fmt.Println("./phonedatatool -query -i phone.dat -number 13000001234,18957509123 15800000000")
+	fmt.Println("./phonedatatool -query -i phone.dat < numbers.txt")
+	fmt.Println("./phonedatatool -embed -i phone.dat -o embedded -package embedded")
+	fmt.Println("./phonedatatool -reverse -i phone.dat -province 浙江 -city 绍兴 -carrier 3")
+	fmt.Println("./phonedatatool -roundtrip-check -i phone.dat")
+	fmt.Println("./phonedatatool -pack -i tmp -o phone.dat -carriers carriers.txt")
 }
 
-func Pack(plainDirectoryPath string, phoneDataFilePath string) error {
+func Pack(plainDirectoryPath string, phoneDataFilePath string, carriers *carrier.Registry) error {
 	if err := util.AssureFileNotExist(phoneDataFilePath); err != nil {
 		return err
 	}
@@ -133,7 +228,15 @@ func Pack(plainDirectoryPath string, phoneDataFilePath string) error {
 		indexPlainTextBuf = buf
 	}
 
-	if buf, err := pack.NewPacker().Pack(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf); err != nil {
+	// 服务号码文件可以没有
+	var servicePlainTextBuf []byte
+	if buf, err := os.ReadFile(path.Join(plainDirectoryPath, ServiceFileName)); err != nil && !os.IsNotExist(err) {
+		return err
+	} else {
+		servicePlainTextBuf = buf
+	}
+
+	if buf, err := pack.NewPackerWithCarriers(carriers).PackWithService(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, servicePlainTextBuf); err != nil {
 		return err
 	} else {
 		return os.WriteFile(phoneDataFilePath, buf, 0)
@@ -148,8 +251,9 @@ func Unpack(phoneDataFilePath string, plainDirectoryPath string) error {
 	versionFilePath := path.Join(plainDirectoryPath, VersionFileName)
 	recordFilePath := path.Join(plainDirectoryPath, RecordFileName)
 	indexFilePath := path.Join(plainDirectoryPath, IndexFileName)
+	serviceFilePath := path.Join(plainDirectoryPath, ServiceFileName)
 
-	if err := util.AssureAllFileNotExist(versionFilePath, recordFilePath, indexFilePath); err != nil {
+	if err := util.AssureAllFileNotExist(versionFilePath, recordFilePath, indexFilePath, serviceFilePath); err != nil {
 		return err
 	}
 
@@ -160,7 +264,7 @@ func Unpack(phoneDataFilePath string, plainDirectoryPath string) error {
 		rawBuf = b
 	}
 
-	if versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, err := pack.NewUnpacker().Unpack(rawBuf); err != nil {
+	if versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, servicePlainTextBuf, err := pack.NewUnpacker().UnpackWithService(rawBuf); err != nil {
 		return err
 	} else {
 		if err := os.WriteFile(versionFilePath, versionPlainTextBuf, 0); err != nil {
@@ -172,26 +276,135 @@ func Unpack(phoneDataFilePath string, plainDirectoryPath string) error {
 		if err := os.WriteFile(indexFilePath, indexPlainTextBuf, 0); err != nil {
 			return err
 		}
+		if err := os.WriteFile(serviceFilePath, servicePlainTextBuf, 0); err != nil {
+			return err
+		}
 		return nil
 	}
 }
 
-func QueryNumber(phoneDataFilePath string, number string) error {
+// queryNumbers 合并 -number 中以逗号分隔的号码和命令行中其余的参数。
+func queryNumbers(numberFlag string, args []string) []string {
+	var numbers []string
+	for _, v := range append(strings.Split(numberFlag, ","), args...) {
+		if v = strings.TrimSpace(v); v != "" {
+			numbers = append(numbers, v)
+		}
+	}
+	return numbers
+}
+
+// readNumbers 从 r 读取号码，每行一个，忽略空行。
+func readNumbers(r io.Reader) ([]string, error) {
+	var numbers []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if v := strings.TrimSpace(scanner.Text()); v != "" {
+			numbers = append(numbers, v)
+		}
+	}
+	return numbers, scanner.Err()
+}
+
+// QueryNumbers 只读取、解析一次二进制文件，然后依次查询 numbers 中的号码。
+// 某个号码查询失败时输出错误并继续查询下一个。
+func QueryNumbers(phoneDataFilePath string, numbers []string, carriers *carrier.Registry) error {
+	var querier phonedatatool.Querier
+	if q, err := pack.OpenQuerier(phoneDataFilePath, reader.WithCarriers(carriers)); err != nil {
+		return err
+	} else {
+		querier = q
+	}
+	for i, number := range numbers {
+		if i > 0 {
+			fmt.Println()
+		}
+		if info, err := querier.QueryNumber(number); err != nil {
+			fmt.Println("ERROR! Query failed.", err)
+		} else {
+			printQueryResult(info)
+		}
+	}
+	return nil
+}
+
+func printQueryResult(info *phonedatatool.QueryResult) {
+	fmt.Println("PhoneNum: ", info.PhoneNum)
+	fmt.Println("AreaZone: ", info.AreaZone)
+	fmt.Println("CardType: ", info.CardType)
+	if c := info.Carrier; c.Known() {
+		fmt.Println("Carrier: ", c.Code, c.EnglishName, "MVNO:", c.MVNO, "Network:", c.Network)
+	}
+	fmt.Println("City: ", info.City)
+	fmt.Println("ZipCode: ", info.ZipCode)
+	fmt.Println("Province: ", info.Province)
+	fmt.Println("NumberType: ", info.Type)
+}
+
+func Embed(phoneDataFilePath string, packageDirectoryPath string, packageName string) error {
+	if err := os.MkdirAll(packageDirectoryPath, 0755); err != nil {
+		return fmt.Errorf("target directory %v not exist and can't be created: %v", packageDirectoryPath, err)
+	}
+
+	phoneDataTargetPath := path.Join(packageDirectoryPath, embedgen.PhoneDataFileName)
+	sourceFilePath := path.Join(packageDirectoryPath, packageName+".go")
+	if err := util.AssureAllFileNotExist(phoneDataTargetPath, sourceFilePath); err != nil {
+		return err
+	}
+
 	var rawBuf []byte
 	if b, err := os.ReadFile(phoneDataFilePath); err != nil {
 		return err
 	} else {
 		rawBuf = b
 	}
-	if info, err := pack.NewQuerier().Query(rawBuf, number); err != nil {
+
+	if source, err := embedgen.Generate(rawBuf, packageName); err != nil {
 		return err
 	} else {
-		fmt.Println("PhoneNum: ", info.PhoneNumber)
-		fmt.Println("AreaZone: ", info.AreaCode)
-		fmt.Println("CardType: ", info.CardTypeID.ToName().String())
-		fmt.Println("City: ", info.CityName)
-		fmt.Println("ZipCode: ", info.ZipCode)
-		fmt.Println("Province: ", info.ProvinceName)
-		return nil
+		if err := os.WriteFile(phoneDataTargetPath, rawBuf, 0644); err != nil {
+			return err
+		}
+		return os.WriteFile(sourceFilePath, source, 0644)
+	}
+}
+
+// RoundTripCheck 校验解包再打包二进制文件得到的内容与原文件逐字节相同，见 pack.CheckRoundTrip。
+func RoundTripCheck(phoneDataFilePath string) error {
+	if buf, err := os.ReadFile(phoneDataFilePath); err != nil {
+		return err
+	} else {
+		return pack.CheckRoundTrip(buf)
+	}
+}
+
+func Reverse(phoneDataFilePath string, province string, city string, cardType string, carriers *carrier.Registry) error {
+	query := phonedata.RegionQuery{Province: province, City: city}
+	if cardType != "" {
+		if c, err := carriers.Parse(cardType); err != nil {
+			return err
+		} else {
+			query.CardType = c.ID
+		}
+	}
+
+	db, err := phonedata.Open(phoneDataFilePath, phonedata.WithCarriers(carriers))
+	if err != nil {
+		return err
+	}
+	ranges, err := db.PrefixRanges(query)
+	if err != nil {
+		return err
+	}
+	var count int32
+	for _, r := range ranges {
+		if r.First == r.Last {
+			fmt.Println(r.First)
+		} else {
+			fmt.Printf("%d-%d\n", r.First, r.Last)
+		}
+		count += r.Count()
 	}
+	fmt.Printf("Total: %d ranges, %d prefixes\n", len(ranges), count)
+	return nil
 }
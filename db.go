@@ -0,0 +1,252 @@
+package phonedata
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/xluohome/phonedata/carrier"
+	"github.com/xluohome/phonedata/reader"
+)
+
+// DB 是一份已加载的 phone.dat 数据，可被多个 goroutine 并发查询。
+// 通过 Open 打开的 DB 可以用 Reload 或 Watch 在运行中更换数据文件。
+type DB struct {
+	path     string
+	opts     options
+	snap     atomic.Value // *snapshot
+	reloadMu sync.Mutex
+}
+
+// snapshot 是某一时刻完整、已校验的数据文件内容，创建后不再修改。
+// 数据文件的解析由 reader.Reader 完成：头部和记录区总是在内存中；
+// 索引区根据 Storage 直接寻址或经由页缓存读取。
+type snapshot struct {
+	rd       *reader.Reader
+	ver      string
+	closer   io.Closer
+	mapping  *mmap // StorageMmap 时的映射，Watch 检查它的文件大小
+	carriers *carrier.Registry
+
+	services  map[string]*ServiceRecord // 服务号码 -> 服务号码记录
+	locations map[int64]*Location       // locationKey -> 归属地，加载时生成，查询时共享
+
+	region_mu sync.Mutex
+	region    *region_index // 反查和固定电话使用的索引，第一次使用时才建立
+
+	dense           []uint16    // WithDenseIndex 时的直接索引表，下标为号码前七位 - DENSE_BASE
+	dense_locations []*Location // 直接索引表的槽位值 - 1 -> 归属地
+}
+
+// Open 打开 path 指向的 phone.dat 文件并返回 DB。默认整个文件读入内存，可用 WithStorage 改变。
+func Open(path string, opts ...Option) (*DB, error) {
+	o := newOptions(opts)
+	s, err := loadSnapshot(path, o)
+	if err != nil {
+		return nil, err
+	}
+	db := &DB{path: path, opts: o}
+	db.snap.Store(s)
+	return db, nil
+}
+
+// Load 以 content 作为 phone.dat 的内容返回 DB。content 在此之后不应再被修改。
+func Load(content []byte, opts ...Option) (*DB, error) {
+	o := newOptions(opts)
+	s, err := newSnapshot(content, nil, nil, o)
+	if err != nil {
+		return nil, err
+	}
+	db := &DB{opts: o}
+	db.snap.Store(s)
+	return db, nil
+}
+
+// LoadReaderAt 从 r 读取长度为 size 的 phone.dat 内容，只在内存中缓存少量页，页数可用 WithPageCache 指定。
+// 查询期间 r 必须保持可读。
+func LoadReaderAt(r io.ReaderAt, size int64, opts ...Option) (*DB, error) {
+	o := newOptions(opts)
+	s, err := newSnapshot(nil, newPageCache(r, size, o.page_count), nil, o)
+	if err != nil {
+		return nil, err
+	}
+	db := &DB{opts: o}
+	db.snap.Store(s)
+	return db, nil
+}
+
+func loadSnapshot(path string, o options) (*snapshot, error) {
+	content, cache, closer, err := openStorage(path, o)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newSnapshot(content, cache, closer, o)
+	if err != nil {
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if closer != nil {
+		// 被 Reload 替换的 snapshot 可能仍有查询在使用，等它不可达时再释放。
+		runtime.SetFinalizer(s, (*snapshot).close)
+	}
+	return s, nil
+}
+
+// newSnapshot 完整校验数据文件：头部、记录区的每一条记录、索引区的每一个索引。
+// cache 不为 nil 时经由 cache 读取，否则 content 即整个文件。
+func newSnapshot(content []byte, cache *pageCache, closer io.Closer, o options) (*snapshot, error) {
+	s := &snapshot{
+		closer:   closer,
+		carriers: o.carriers,
+	}
+	var err error
+	if cache == nil {
+		s.rd, err = reader.New(content, reader.WithCarriers(o.carriers))
+	} else {
+		s.rd, err = reader.NewReaderAt(cache, cache.size, reader.WithCarriers(o.carriers))
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.ver = s.rd.Version()
+	s.mapping, _ = closer.(*mmap)
+
+	s.services = make(map[string]*ServiceRecord)
+	services := s.rd.Services()
+	for i := range services {
+		s.services[services[i].Number] = &services[i]
+	}
+
+	s.locations = make(map[int64]*Location)
+	dense_ids := make(map[int64]uint16)
+	err = s.rd.Each(func(i int32, e reader.Entry) error {
+		key := locationKey(e.RecordOffset, e.CardType)
+		loc := s.locations[key]
+		if loc == nil {
+			r, _ := s.rd.Record(e.RecordOffset)
+			loc = newLocation(r, s.carriers.Of(e.CardType))
+			s.locations[key] = loc
+		}
+		if o.dense {
+			if err := s.setDense(e.Prefix, key, loc, dense_ids); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *snapshot) close() error {
+	runtime.SetFinalizer(s, nil)
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+func (db *DB) snapshot() *snapshot {
+	return db.snap.Load().(*snapshot)
+}
+
+// Close 释放 DB 占用的文件和映射。Close 之后不能再使用 DB。
+func (db *DB) Close() error {
+	db.reloadMu.Lock()
+	defer db.reloadMu.Unlock()
+	return db.snapshot().close()
+}
+
+// Storage 返回 DB 访问数据文件的方式。
+func (db *DB) Storage() Storage {
+	return db.opts.storage
+}
+
+// Version 返回数据文件的版本号，如 "2108"。
+func (db *DB) Version() string {
+	return db.snapshot().version()
+}
+
+// Count 返回索引区中号段（号码前七位）的条数。
+func (db *DB) Count() int32 {
+	return db.snapshot().count()
+}
+
+// FirstRecordOffset 返回索引区的起始偏移。
+func (db *DB) FirstRecordOffset() int32 {
+	return db.snapshot().rd.IndexOffset()
+}
+
+// Find 用二分法查询号码归属地。号码先经过 Normalize 整理，PhoneRecord.PhoneNum 为整理后的号码。
+// 以 0 开头的号码按固定电话查询，见 FindLandline；14x、1064x 号段的 13 位号码按物联网号码查询，
+// 只返回运营商，PhoneRecord.Type 为 IoT。
+func (db *DB) Find(phone_num string) (pr *PhoneRecord, err error) {
+	return db.snapshot().find(phone_num)
+}
+
+func (s *snapshot) version() string {
+	return s.ver
+}
+
+func (s *snapshot) count() int32 {
+	return s.rd.Count()
+}
+
+func (s *snapshot) find(number string) (*PhoneRecord, error) {
+	if national, err := Normalize(number); err == nil && len(national) > 0 && national[0] == '0' {
+		return s.findLandline(number)
+	} else if err == nil && len(national) == IOT_PHONE_LENGTH {
+		return reader.FindIoT(number, national, s.carriers)
+	}
+	phone_num, loc, err := s.lookup(number)
+	if err != nil {
+		return nil, err
+	}
+	return &PhoneRecord{
+		PhoneNum: phone_num,
+		Province: loc.Province,
+		City:     loc.City,
+		ZipCode:  loc.ZipCode,
+		AreaZone: loc.AreaZone,
+		CardType: loc.CardType,
+		Carrier:  loc.Carrier,
+		Type:     Mobile,
+	}, nil
+}
+
+// lookup 用二分法查询号码，返回整理后的号码和共享的归属地，查询成功时不分配内存。
+func (s *snapshot) lookup(number string) (phone_num string, loc *Location, err error) {
+	defer runtime.KeepAlive(s)
+	phone_num, err = Normalize(number)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(phone_num) < 7 || len(phone_num) > 11 {
+		return "", nil, lookupError(number, ErrInvalidLength)
+	}
+
+	phone_seven, ok := reader.ParsePrefix(phone_num)
+	if !ok {
+		return "", nil, lookupError(number, ErrInvalidNumber)
+	}
+	if s.dense != nil {
+		if loc := s.lookupDense(phone_seven); loc != nil {
+			return phone_num, loc, nil
+		}
+		return "", nil, lookupError(number, ErrNotFound)
+	}
+	e, found, err := s.rd.Search(phone_seven)
+	if err != nil {
+		return "", nil, err
+	}
+	if !found {
+		return "", nil, lookupError(number, ErrNotFound)
+	}
+	return phone_num, s.locations[locationKey(e.RecordOffset, e.CardType)], nil
+}
@@ -0,0 +1,46 @@
+package phonedata
+
+import (
+	"testing"
+)
+
+func TestOpenMissingFile(t *testing.T) {
+	_, err := Open("not_exist/phone.dat")
+	if err == nil {
+		t.Fatal("错误的结果")
+	}
+	t.Log(err)
+}
+
+func TestLoadIllegalContent(t *testing.T) {
+	for _, content := range [][]byte{
+		nil,
+		[]byte("2108"),
+		[]byte("2108\xff\x00\x00\x00"),
+		[]byte("2108\x08\x00\x00\x00\x01\x02"),
+	} {
+		if _, err := Load(content); err == nil {
+			t.Fatalf("错误的结果: %q", content)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	db, err := Open(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Version() != "2108" {
+		t.Fatal("版本号错误", db.Version())
+	}
+	if db.Count() != 454336 {
+		t.Fatal("号段条数错误", db.Count())
+	}
+	pr, err := db.Find("18957509123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Province != "浙江" || pr.City != "绍兴" || pr.CardType != "中国电信" {
+		t.Fatal("验证失败", pr)
+	}
+}
@@ -0,0 +1,43 @@
+package phonedata
+
+import (
+	"fmt"
+)
+
+const (
+	DENSE_BASE = 1000000 // 号码前七位的最小值
+	DENSE_SIZE = 1000000 // 直接索引表的槽位数，覆盖 1000000–1999999
+)
+
+// setDense 把号码前七位 phone 的槽位指向 loc。槽位保存归属地编号 + 1，0 表示没有该号段；
+// 每个归属地对应一条记录和一种卡类型。
+// dense_ids 为 locationKey -> 归属地编号 + 1，只在加载时使用。
+func (s *snapshot) setDense(phone int32, key int64, loc *Location, dense_ids map[int64]uint16) error {
+	if phone < DENSE_BASE || phone >= DENSE_BASE+DENSE_SIZE {
+		return fmt.Errorf("prefix %d out of range for dense index", phone)
+	}
+	if s.dense == nil {
+		s.dense = make([]uint16, DENSE_SIZE)
+	}
+	id, ok := dense_ids[key]
+	if !ok {
+		if len(s.dense_locations) >= 1<<16-1 {
+			return fmt.Errorf("too many locations for dense index")
+		}
+		s.dense_locations = append(s.dense_locations, loc)
+		id = uint16(len(s.dense_locations))
+		dense_ids[key] = id
+	}
+	s.dense[phone-DENSE_BASE] = id
+	return nil
+}
+
+func (s *snapshot) lookupDense(phone int32) *Location {
+	if phone < DENSE_BASE || phone >= DENSE_BASE+DENSE_SIZE {
+		return nil
+	}
+	if id := s.dense[phone-DENSE_BASE]; id != 0 {
+		return s.dense_locations[id-1]
+	}
+	return nil
+}
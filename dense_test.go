@@ -0,0 +1,92 @@
+package phonedata
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestDenseIndex(t *testing.T) {
+	binary, err := Open(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dense, err := Open(PHONE_DAT, WithDenseIndex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dense.snapshot().dense == nil {
+		t.Fatal("没有建立直接索引表")
+	}
+	for prefix := 999990; prefix < 2000010; prefix += 7 {
+		number := fmt.Sprintf("%d", prefix)
+		expected, expectedErr := binary.Lookup(number)
+		loc, err := dense.Lookup(number)
+		if fmt.Sprint(expectedErr) != fmt.Sprint(err) || fmt.Sprint(expected) != fmt.Sprint(loc) {
+			t.Fatal(number, "验证失败", loc, err)
+		}
+	}
+}
+
+func TestDenseIndexOutOfRange(t *testing.T) {
+	content := []byte("2108\x10\x00\x00\x00a|b|c|d\x00\x40\x42\x0f\x00\x08\x00\x00\x00\x01\x80\x84\x1e\x00\x08\x00\x00\x00\x01")
+	if _, err := Load(content); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(content, WithDenseIndex()); err == nil {
+		t.Fatal("错误的结果")
+	}
+}
+
+func benchmarkLookup(b *testing.B, opts ...Option) {
+	db, err := Open(PHONE_DAT, opts...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	numbers := lookupNumbers()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.Lookup(numbers[i%len(numbers)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLookupBinarySearch(b *testing.B) {
+	benchmarkLookup(b)
+}
+
+func BenchmarkLookupDenseIndex(b *testing.B) {
+	benchmarkLookup(b, WithDenseIndex())
+}
+
+// benchmarkLoad 报告加载一次数据文件分配的内存，以及索引本身占用的字节数（index-B）。
+func benchmarkLoad(b *testing.B, opts ...Option) {
+	content, err := ioutil.ReadFile(PHONE_DAT)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	var db *DB
+	for i := 0; i < b.N; i++ {
+		if db, err = Load(content, opts...); err != nil {
+			b.Fatal(err)
+		}
+	}
+	s := db.snapshot()
+	index_bytes := int(s.rd.Size() - s.rd.IndexOffset())
+	if s.dense != nil {
+		index_bytes += len(s.dense) * 2
+	}
+	b.ReportMetric(float64(index_bytes), "index-B")
+}
+
+func BenchmarkLoadBinarySearch(b *testing.B) {
+	benchmarkLoad(b)
+}
+
+func BenchmarkLoadDenseIndex(b *testing.B) {
+	benchmarkLoad(b, WithDenseIndex())
+}
@@ -0,0 +1,20 @@
+package phonedata
+
+import (
+	"github.com/xluohome/phonedata/reader"
+)
+
+// 查询失败的原因，可以用 errors.Is 判断。与 reader 包中的同名变量相同。
+var (
+	ErrInvalidLength = reader.ErrInvalidLength // 号码长度不合法
+	ErrInvalidNumber = reader.ErrInvalidNumber // 号码含有非数字字符
+	ErrNotFound      = reader.ErrNotFound      // 号码格式正确，但数据文件中没有该号段
+	ErrInternational = reader.ErrInternational // 国外号码
+)
+
+// LookupError 记录查询失败的号码和原因，可以用 errors.As 取出。
+type LookupError = reader.LookupError
+
+func lookupError(number string, err error) error {
+	return &LookupError{Number: number, Err: err}
+}
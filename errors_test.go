@@ -0,0 +1,24 @@
+package phonedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupError(t *testing.T) {
+	for number, reason := range map[string]error{
+		"13580198235123123213213": ErrInvalidLength,
+		"1300":                    ErrInvalidLength,
+		"afsd32323":               ErrInvalidNumber,
+		"10074872323":             ErrNotFound,
+	} {
+		_, err := Find(number)
+		if !errors.Is(err, reason) {
+			t.Fatal(number, "错误的结果", err)
+		}
+		var lookupErr *LookupError
+		if !errors.As(err, &lookupErr) || lookupErr.Number != number || lookupErr.Err != reason {
+			t.Fatal(number, "错误的结果", err)
+		}
+	}
+}
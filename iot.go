@@ -0,0 +1,13 @@
+package phonedata
+
+import (
+	"github.com/xluohome/phonedata/reader"
+)
+
+const IOT_PHONE_LENGTH = reader.IoTPhoneLength // 物联网号码的长度
+
+// IoTCardType 返回 13 位物联网号码 phone_num 所属运营商的卡类型。
+// 号码不是 13 位数字、不在已知的物联网号段时 ok 为 false。
+func IoTCardType(phone_num string) (card_type byte, ok bool) {
+	return reader.IoTCardType(phone_num)
+}
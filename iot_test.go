@@ -0,0 +1,33 @@
+package phonedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFindIoT(t *testing.T) {
+	for number, card_type := range map[string]byte{
+		"1440123456789":     CMCC,
+		"1064812345678":     CMCC,
+		"1481234567890":     CMCC,
+		"1064612345678":     CUCC,
+		"1461234567890":     CUCC,
+		"1410123456789":     CTCC,
+		"+86 1064912345678": CTCC,
+	} {
+		pr, err := Find(number)
+		if err != nil {
+			t.Fatal(number, err)
+		}
+		if pr.Type != IoT || pr.CardType != CardTypemap[card_type] || pr.Carrier.ID != card_type || pr.Province != "" {
+			t.Fatal(number, "验证失败", pr)
+		}
+	}
+
+	if _, err := Find("1420123456789"); !errors.Is(err, ErrNotFound) {
+		t.Fatal("错误的结果", err)
+	}
+	if _, err := Find("142012345678"); !errors.Is(err, ErrInvalidLength) {
+		t.Fatal("错误的结果", err)
+	}
+}
@@ -0,0 +1,85 @@
+package phonedata
+
+const (
+	MIN_SUBSCRIBER_LENGTH = 7 // 固定电话本地号码的最短长度
+	MAX_SUBSCRIBER_LENGTH = 8 // 固定电话本地号码的最长长度
+)
+
+// ParseLandline 把固定电话号码拆分成长途区号和本地号码，如 "0571-88888888" 拆成 "0571" 和 "88888888"。
+// 010 和 02x 是三位区号，其余以 0 开头的是四位区号。号码先经过 Normalize 整理。
+func ParseLandline(number string) (area_code, subscriber string, err error) {
+	national, err := Normalize(number)
+	if err != nil {
+		return "", "", err
+	}
+	return splitLandline(number, national)
+}
+
+// splitLandline 拆分已经整理过的固定电话号码 national，出错时返回的 LookupError 记录原始号码 number。
+func splitLandline(number, national string) (area_code, subscriber string, err error) {
+	if len(national) < 3 || national[0] != '0' || national[1] == '0' {
+		return "", "", lookupError(number, ErrInvalidNumber)
+	}
+	area_len := 4
+	if national[1] == '1' || national[1] == '2' {
+		area_len = 3
+	}
+	if n := len(national) - area_len; n < MIN_SUBSCRIBER_LENGTH || n > MAX_SUBSCRIBER_LENGTH {
+		return "", "", lookupError(number, ErrInvalidLength)
+	}
+	if national[area_len] == '0' {
+		// 本地号码不以 0 开头
+		return "", "", lookupError(number, ErrInvalidNumber)
+	}
+	return national[:area_len], national[area_len:], nil
+}
+
+// buildAreaIndex 为每个长途区号选出号段最多的记录作为该区号的主要城市，
+// 例如 0551 对应合肥而不是巢湖。
+func (idx *region_index) buildAreaIndex() {
+	idx.area_primary = make(map[string]int32)
+	for area, record_offsets := range idx.area_records {
+		best, best_count := record_offsets[0], int32(-1)
+		for _, record_offset := range record_offsets {
+			var count int32
+			for _, r := range idx.ranges[record_offset] {
+				count += r.Count()
+			}
+			if count > best_count {
+				best, best_count = record_offset, count
+			}
+		}
+		idx.area_primary[area] = best
+	}
+}
+
+func (s *snapshot) findLandline(number string) (*PhoneRecord, error) {
+	area_code, subscriber, err := ParseLandline(number)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := s.regionIndex()
+	if err != nil {
+		return nil, err
+	}
+	record_offset, ok := idx.area_primary[area_code]
+	if !ok {
+		return nil, lookupError(number, ErrNotFound)
+	}
+	r, _ := s.rd.Record(record_offset)
+	return &PhoneRecord{
+		PhoneNum: area_code + subscriber,
+		Province: r.Province,
+		City:     r.City,
+		ZipCode:  r.ZipCode,
+		AreaZone: r.AreaZone,
+		Type:     FixedLine,
+	}, nil
+}
+
+// FindLandline 查询固定电话号码的归属地，如 "0571-88888888"、"01012345678"。
+// 通过记录区中的长途区号确定地区；一个区号对应多个城市时返回号段最多的城市。
+// 返回的 PhoneRecord.Type 为 FixedLine，CardType 为空。
+func (db *DB) FindLandline(number string) (*PhoneRecord, error) {
+	return db.snapshot().findLandline(number)
+}
@@ -0,0 +1,62 @@
+package phonedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseLandline(t *testing.T) {
+	for number, expected := range map[string][2]string{
+		"0571-88888888":     {"0571", "88888888"},
+		"01012345678":       {"010", "12345678"},
+		"(020) 8888 8888":   {"020", "88888888"},
+		"+86 575 8512345":   {"0575", "8512345"},
+		"0086-10-6512-3456": {"010", "65123456"},
+		"０５５１－６５１２３４５６": {"0551", "65123456"},
+	} {
+		area_code, subscriber, err := ParseLandline(number)
+		if err != nil {
+			t.Fatal(number, err)
+		}
+		if area_code != expected[0] || subscriber != expected[1] {
+			t.Fatal(number, "验证失败", area_code, subscriber)
+		}
+	}
+
+	for number, reason := range map[string]error{
+		"0571-888888":     ErrInvalidLength,
+		"0571-888888888":  ErrInvalidLength,
+		"010-123456789":   ErrInvalidLength,
+		"0571-08888888":   ErrInvalidNumber,
+		"18957509123":     ErrInvalidNumber,
+		"00571-88888888x": ErrInvalidNumber,
+	} {
+		if _, _, err := ParseLandline(number); !errors.Is(err, reason) {
+			t.Fatal(number, "错误的结果", err)
+		}
+	}
+}
+
+func TestFindLandline(t *testing.T) {
+	for number, expected := range map[string][3]string{
+		"0571-88888888": {"057188888888", "浙江", "杭州"},
+		"01012345678":   {"01012345678", "北京", "北京"},
+		"0551-65123456": {"055165123456", "安徽", "合肥"},
+	} {
+		pr, err := Find(number)
+		if err != nil {
+			t.Fatal(number, err)
+		}
+		if pr.Type != FixedLine || pr.PhoneNum != expected[0] || pr.Province != expected[1] || pr.City != expected[2] {
+			t.Fatal(number, "验证失败", pr)
+		}
+	}
+
+	if _, err := Find("0600-1234567"); !errors.Is(err, ErrNotFound) {
+		t.Fatal("错误的结果", err)
+	}
+	pr, err := Find("18957509123")
+	if err != nil || pr.Type != Mobile {
+		t.Fatal("验证失败", pr, err)
+	}
+}
@@ -0,0 +1,51 @@
+package phonedata
+
+import (
+	"github.com/xluohome/phonedata/reader"
+)
+
+// Location 是号段的归属地和卡类型。
+// 同一份数据中相同的记录和卡类型共享同一个 *Location，在加载时解码一次，调用方不能修改。
+type Location struct {
+	Province string
+	City     string
+	ZipCode  string
+	AreaZone string
+	CardType string
+	Carrier  Carrier
+}
+
+func (loc *Location) String() string {
+	return loc.Province + " " + loc.City + " " + loc.ZipCode + " " + loc.AreaZone + " " + loc.CardType
+}
+
+func locationKey(record_offset int32, card_type byte) int64 {
+	return int64(record_offset)<<8 | int64(card_type)
+}
+
+func newLocation(r *reader.Record, c Carrier) *Location {
+	return &Location{
+		Province: r.Province,
+		City:     r.City,
+		ZipCode:  r.ZipCode,
+		AreaZone: r.AreaZone,
+		CardType: c.Name,
+		Carrier:  c,
+	}
+}
+
+// Lookup 查询号码的归属地。与 Find 相比，Lookup 返回加载时预先解码的共享值，
+// 对已经是纯数字的号码查询成功时不分配内存，适合高 QPS 的场景。
+func (db *DB) Lookup(phone_num string) (*Location, error) {
+	_, loc, err := db.snapshot().lookup(phone_num)
+	return loc, err
+}
+
+// Lookup 在默认 DB 中查询号码的归属地，见 (*DB).Lookup。
+func Lookup(phone_num string) (*Location, error) {
+	db, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return db.Lookup(phone_num)
+}
@@ -0,0 +1,25 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package phonedata
+
+import (
+	"errors"
+	"os"
+)
+
+type mmap struct {
+	data []byte
+}
+
+func mmapFile(f *os.File) (*mmap, error) {
+	return nil, errors.New("mmap is not supported on this platform")
+}
+
+func (m *mmap) changed() bool {
+	return false
+}
+
+func (m *mmap) Close() error {
+	return nil
+}
@@ -0,0 +1,53 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package phonedata
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// mmap 是只读映射到内存的文件内容。文件保持打开，用于检查文件是否被原地改写。
+type mmap struct {
+	data []byte
+	f    *os.File
+	fd   int
+}
+
+// mmapFile 映射 f 的全部内容，成功后 f 由返回的 mmap 负责关闭。
+func mmapFile(f *os.File) (*mmap, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := fi.Size()
+	if size <= 0 || size != int64(int(size)) {
+		return nil, fmt.Errorf("can't mmap %s of size %d", f.Name(), size)
+	}
+	fd := int(f.Fd())
+	b, err := syscall.Mmap(fd, 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap %s: %v", f.Name(), err)
+	}
+	return &mmap{data: b, f: f, fd: fd}, nil
+}
+
+// changed 判断映射的文件大小是否与映射时不同。文件被原地截短后访问超出文件末尾的映射会导致 SIGBUS。
+// 大小不变的原地改写无法发现，见 WithStorage。
+func (m *mmap) changed() bool {
+	var st syscall.Stat_t
+	if err := syscall.Fstat(m.fd, &st); err != nil {
+		return true
+	}
+	return st.Size != int64(len(m.data))
+}
+
+func (m *mmap) Close() error {
+	err := syscall.Munmap(m.data)
+	if e := m.f.Close(); err == nil {
+		err = e
+	}
+	return err
+}
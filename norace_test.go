@@ -0,0 +1,6 @@
+//go:build !race
+// +build !race
+
+package phonedata
+
+const raceEnabled = false
@@ -0,0 +1,138 @@
+package phonedata
+
+import (
+	"strings"
+)
+
+// NumberFormat 是 Format 输出号码的格式。
+type NumberFormat int
+
+const (
+	E164     NumberFormat = iota // 国际格式，如 +8618957509123
+	National                     // 国内格式，如 189 5750 9123、0571 88888888
+)
+
+const COUNTRY_CODE = "86"
+
+// Normalize 把实际使用中的各种号码写法整理成国内格式的纯数字号码：
+// 去掉空格、横线、括号、点等分隔符，把全角数字转成半角，去掉 +86、0086 和 86 前缀。
+// 例如 "+86 189-5750-9123"、"008618957509123"、"86-18957509123" 都整理成 "18957509123"，
+// "86 571 88888888" 整理成 "057188888888"。
+// 含有其他字符、+ 号位置不对或 86 后面不是手机号码、物联网号码、固定电话时返回 ErrInvalidNumber，
+// 国外号码返回 ErrInternational。Normalize 不检查号码长度，检查号码类型见 Classify。
+func Normalize(number string) (string, error) {
+	digits, plus, ok := normalizeDigits(number)
+	if !ok || digits == "" {
+		return "", lookupError(number, ErrInvalidNumber)
+	}
+
+	var national string
+	switch {
+	case plus:
+		national = digits
+	case strings.HasPrefix(digits, "00"):
+		national = digits[2:]
+	case len(digits) > MAX_SUBSCRIBER_LENGTH && strings.HasPrefix(digits, COUNTRY_CODE):
+		// 没有 + 号的 86 前缀比本地号码长，只能是国家代码
+		return trimCountryCode(number, digits[len(COUNTRY_CODE):])
+	default:
+		return digits, nil
+	}
+
+	// 带国际冠码的号码
+	if !strings.HasPrefix(national, COUNTRY_CODE) {
+		return "", lookupError(number, ErrInternational)
+	}
+	national = national[len(COUNTRY_CODE):]
+	switch {
+	case national == "":
+		return "", lookupError(number, ErrInvalidNumber)
+	case national[0] == '1' && national[1:2] == "0" && len(national) == 10:
+		// 北京的固定电话，如 +86 10 6512 3456，补回长途冠码 0
+		return "0" + national, nil
+	case national[0] == '0' || national[0] == '1':
+		return national, nil
+	default:
+		// 固定电话去掉了长途冠码 0，补回来
+		return "0" + national, nil
+	}
+}
+
+// trimCountryCode 检查去掉没有 + 号的 86 前缀后的号码 national，必须是手机号码、物联网号码或固定电话，
+// 固定电话可以省略长途冠码 0。其他号码无法确定 86 是不是国家代码，返回 ErrInvalidNumber。
+func trimCountryCode(number, national string) (string, error) {
+	if isMobileNumber(national) || isIoTNumber(national) {
+		return national, nil
+	}
+	if national[0] != '0' {
+		national = "0" + national
+	}
+	if _, _, err := splitLandline(number, national); err != nil {
+		return "", lookupError(number, ErrInvalidNumber)
+	}
+	return national, nil
+}
+
+// normalizeDigits 去掉分隔符并把全角字符转成半角。plus 表示号码以 + 开头。
+// 如果 number 本身就是纯半角数字，直接返回 number，不分配内存。
+func normalizeDigits(number string) (digits string, plus bool, ok bool) {
+	i := 0
+	for i < len(number) && '0' <= number[i] && number[i] <= '9' {
+		i++
+	}
+	if i == len(number) {
+		return number, false, true
+	}
+
+	var b strings.Builder
+	b.Grow(len(number))
+	b.WriteString(number[:i])
+	for _, r := range number[i:] {
+		switch {
+		case '0' <= r && r <= '9':
+			b.WriteRune(r)
+		case '０' <= r && r <= '９':
+			b.WriteRune(r - '０' + '0')
+		case r == '+' || r == '＋':
+			if plus || b.Len() > 0 {
+				return "", false, false
+			}
+			plus = true
+		case strings.ContainsRune(" \t-().\u00a0\u3000－（）．", r):
+		default:
+			return "", false, false
+		}
+	}
+	return b.String(), plus, true
+}
+
+// Format 把号码整理后按 format 输出。只能输出手机号码、固定电话、服务号码和物联网号码，
+// 其他号码返回 ErrInvalidNumber，国外号码返回 ErrInternational。
+func Format(number string, format NumberFormat) (string, error) {
+	c := classify(nil, number)
+	switch c.Type {
+	case Mobile, FixedLine, Service, IoT:
+	case International:
+		return "", lookupError(number, ErrInternational)
+	default:
+		return "", lookupError(number, ErrInvalidNumber)
+	}
+	national := c.Normalized
+	switch format {
+	case E164:
+		return "+" + COUNTRY_CODE + strings.TrimPrefix(national, "0"), nil
+	case National:
+		switch {
+		case len(national) == 11 && national[0] == '1':
+			return national[:3] + " " + national[3:7] + " " + national[7:], nil
+		case len(national) > 4 && national[0] == '0' && (national[1] == '1' || national[1] == '2'):
+			return national[:3] + " " + national[3:], nil
+		case len(national) > 5 && national[0] == '0':
+			return national[:4] + " " + national[4:], nil
+		default:
+			return national, nil
+		}
+	default:
+		return "", lookupError(number, ErrInvalidNumber)
+	}
+}
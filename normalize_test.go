@@ -0,0 +1,114 @@
+package phonedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	for number, expected := range map[string]string{
+		"18957509123":         "18957509123",
+		"+86 189-5750-9123":   "18957509123",
+		"008618957509123":     "18957509123",
+		"86-18957509123":      "18957509123",
+		"１８９５７５０９１２３":         "18957509123",
+		"＋８６ １８９５７５０９１２３":     "18957509123",
+		"(189) 5750.9123":     "18957509123",
+		"1703576":             "1703576",
+		"+86 571 88888888":    "057188888888",
+		"+86 (0571) 88888888": "057188888888",
+		"0571-88888888":       "057188888888",
+		"8657188888888":       "057188888888",
+		"86 0571 88888888":    "057188888888",
+		"861012345678":        "01012345678",
+		"8614400001234":       "14400001234",
+		"86123456":            "86123456",
+	} {
+		actual, err := Normalize(number)
+		if err != nil {
+			t.Fatal(number, err)
+		}
+		if actual != expected {
+			t.Fatal(number, "验证失败", actual)
+		}
+	}
+
+	for number, reason := range map[string]error{
+		"":                 ErrInvalidNumber,
+		" - ":              ErrInvalidNumber,
+		"afsd32323":        ErrInvalidNumber,
+		"189+5750":         ErrInvalidNumber,
+		"++8618957509123":  ErrInvalidNumber,
+		"+86":              ErrInvalidNumber,
+		"865718888":        ErrInvalidNumber,
+		"8618957509123456": ErrInvalidNumber,
+		"86057100000000":   ErrInvalidNumber,
+		"+1 650 253 0000":  ErrInternational,
+		"0014155552671":    ErrInternational,
+		"18957509123 ext1": ErrInvalidNumber,
+	} {
+		_, err := Normalize(number)
+		if !errors.Is(err, reason) {
+			t.Fatal(number, "错误的结果", err)
+		}
+	}
+}
+
+func TestFindNormalized(t *testing.T) {
+	pr, err := Find("+86 189-5750-9123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.PhoneNum != "18957509123" || pr.City != "绍兴" {
+		t.Fatal("验证失败", pr)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for _, c := range []struct {
+		number   string
+		format   NumberFormat
+		expected string
+	}{
+		{"189-5750-9123", E164, "+8618957509123"},
+		{"189-5750-9123", National, "189 5750 9123"},
+		{"0571-88888888", E164, "+8657188888888"},
+		{"+86 571 88888888", National, "0571 88888888"},
+		{"010 12345678", National, "010 12345678"},
+		{"8657188888888", National, "0571 88888888"},
+		{"10086", E164, "+8610086"},
+	} {
+		actual, err := Format(c.number, c.format)
+		if err != nil {
+			t.Fatal(c.number, err)
+		}
+		if actual != c.expected {
+			t.Fatal(c.number, "验证失败", actual)
+		}
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	for number, reason := range map[string]error{
+		"0":               ErrInvalidNumber,
+		"86":              ErrInvalidNumber,
+		"18957509123456":  ErrInvalidNumber,
+		"0571-888":        ErrInvalidNumber,
+		"+1 650 253 0000": ErrInternational,
+	} {
+		for _, format := range []NumberFormat{E164, National} {
+			if actual, err := Format(number, format); !errors.Is(err, reason) {
+				t.Fatal(number, "错误的结果", actual, err)
+			}
+		}
+	}
+}
+
+func TestNormalizeNoAlloc(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		Normalize("18957509123")
+	})
+	if allocs != 0 {
+		t.Fatal("验证失败", allocs)
+	}
+}
@@ -1,22 +1,23 @@
 package phonedata
 
 import (
-	"bytes"
-	"errors"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"path"
-	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/xluohome/phonedata/carrier"
+	"github.com/xluohome/phonedata/reader"
 )
 
 const (
-	CMCC               byte = iota + 0x01 //中国移动
-	CUCC                                  //中国联通
-	CTCC                                  //中国电信
-	CTCC_v                                //电信虚拟运营商
-	CUCC_v                                //联通虚拟运营商
-	CMCC_v                                //移动虚拟运营商
+	CMCC               = carrier.CMCC   //中国移动
+	CUCC               = carrier.CUCC   //中国联通
+	CTCC               = carrier.CTCC   //中国电信
+	CTCC_v             = carrier.CTCC_v //电信虚拟运营商
+	CUCC_v             = carrier.CUCC_v //联通虚拟运营商
+	CMCC_v             = carrier.CMCC_v //移动虚拟运营商
+	CBN                = carrier.CBN    //中国广电
+	CBN_v              = carrier.CBN_v  //广电虚拟运营商
 	INT_LEN            = 4
 	CHAR_LEN           = 1
 	HEAD_LENGTH        = 8
@@ -24,161 +25,87 @@ const (
 	PHONE_DAT          = "phone.dat"
 )
 
-type PhoneRecord struct {
-	PhoneNum string
-	Province string
-	City     string
-	ZipCode  string
-	AreaZone string
-	CardType string
-}
+// PhoneRecord 是号码查询的结果，与 phonedatatool.QueryResult 相同，见 reader.PhoneRecord。
+type PhoneRecord = reader.PhoneRecord
 
-var (
-	content     []byte
-	CardTypemap = map[byte]string{
-		CMCC:   "中国移动",
-		CUCC:   "中国联通",
-		CTCC:   "中国电信",
-		CTCC_v: "中国电信虚拟运营商",
-		CUCC_v: "中国联通虚拟运营商",
-		CMCC_v: "中国移动虚拟运营商",
-	}
-	total_len, firstoffset int32
-)
+// NumberType 是号码的类型。
+type NumberType = reader.NumberType
 
-func init() {
-	dir := os.Getenv("PHONE_DATA_DIR")
-	if dir == "" {
-		_, fulleFilename, _, _ := runtime.Caller(0)
-		dir = path.Dir(fulleFilename)
-	}
-	var err error
-	content, err = ioutil.ReadFile(path.Join(dir, PHONE_DAT))
-	if err != nil {
-		panic(err)
-	}
-	total_len = int32(len(content))
-	firstoffset = get4(content[INT_LEN : INT_LEN*2])
-}
+const (
+	Mobile        = reader.Mobile        // 手机号码
+	FixedLine     = reader.FixedLine     // 固定电话
+	Service       = reader.Service       // 短号码、服务号码，如 10086、95588、12345、400/800
+	IoT           = reader.IoT           // 13 位物联网号码
+	International = reader.International // 国外号码
+	Invalid       = reader.Invalid       // 无效号码
+)
 
-func Debug() {
-	fmt.Println(version())
-	fmt.Println(totalRecord())
-	fmt.Println(firstRecordOffset())
-}
+var (
+	// Deprecated: CardTypemap 是可以被任意修改的全局变量，查询结果不再使用它。
+	// 使用 CarrierOf 或 carrier.Default() 查询运营商名称。
+	CardTypemap = cardTypeNames()
 
-func (pr PhoneRecord) String() string {
-	return fmt.Sprintf("PhoneNum: %s\nAreaZone: %s\nCardType: %s\nCity: %s\nZipCode: %s\nProvince: %s\n", pr.PhoneNum, pr.AreaZone, pr.CardType, pr.City, pr.ZipCode, pr.Province)
-}
+	defaultMu sync.Mutex
+	defaultDB atomic.Value // *DB，为 nil 时表示还没有加载
+)
 
-func get4(b []byte) int32 {
-	if len(b) < 4 {
-		return 0
+// cardTypeNames 由内置的运营商注册表生成 CardTypemap。
+func cardTypeNames() map[byte]string {
+	names := make(map[byte]string)
+	for _, c := range carrier.Default().All() {
+		names[c.ID] = c.Name
 	}
-	return int32(b[0]) | int32(b[1])<<8 | int32(b[2])<<16 | int32(b[3])<<24
+	return names
 }
 
-func getN(s string) (uint32, error) {
-	var n, cutoff, maxVal uint32
-	i := 0
-	base := 10
-	cutoff = (1<<32-1)/10 + 1
-	maxVal = 1<<uint(32) - 1
-	for ; i < len(s); i++ {
-		var v byte
-		d := s[i]
-		switch {
-		case '0' <= d && d <= '9':
-			v = d - '0'
-		case 'a' <= d && d <= 'z':
-			v = d - 'a' + 10
-		case 'A' <= d && d <= 'Z':
-			v = d - 'A' + 10
-		default:
-			return 0, errors.New("invalid syntax")
-		}
-		if v >= byte(base) {
-			return 0, errors.New("invalid syntax")
-		}
-
-		if n >= cutoff {
-			// n*base overflows
-			n = (1<<32 - 1)
-			return n, errors.New("value out of range")
-		}
-		n *= uint32(base)
-
-		n1 := n + uint32(v)
-		if n1 < n || n1 > maxVal {
-			// n+v overflows
-			n = (1<<32 - 1)
-			return n, errors.New("value out of range")
-		}
-		n = n1
+// Default 返回默认 DB。第一次调用时才在 SearchPaths 中查找并加载 phone.dat，
+// 加载失败时返回错误而不是 panic。只有加载成功的结果会被保留，
+// 失败后的每次调用都会重新查找，数据文件出现后即可使用。
+func Default() (*DB, error) {
+	if db, ok := defaultDB.Load().(*DB); ok && db != nil {
+		return db, nil
 	}
-	return n, nil
-}
-
-func version() string {
-	return string(content[0:INT_LEN])
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if db, ok := defaultDB.Load().(*DB); ok && db != nil {
+		return db, nil
+	}
+	file, err := FindDataFile()
+	if err != nil {
+		return nil, err
+	}
+	db, err := Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defaultDB.Store(db)
+	return db, nil
 }
 
-func totalRecord() int32 {
-	return (int32(len(content)) - firstRecordOffset()) / PHONE_INDEX_LENGTH
+// SetDefault 让 Find 使用 db 作为默认 DB，例如 embedded.DB()。
+// db 为 nil 时清除默认 DB，下次调用 Default 时重新查找并加载。
+func SetDefault(db *DB) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultDB.Store(db)
 }
 
-func firstRecordOffset() int32 {
-	return get4(content[INT_LEN : INT_LEN*2])
+func Debug() {
+	db, err := Default()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(db.Version())
+	fmt.Println(db.Count())
+	fmt.Println(db.FirstRecordOffset())
 }
 
-// 二分法查询phone数据
+// Find 在默认 DB 中查询号码归属地。
 func Find(phone_num string) (pr *PhoneRecord, err error) {
-	if len(phone_num) < 7 || len(phone_num) > 11 {
-		return nil, errors.New("illegal phone length")
-	}
-
-	var left int32
-	phone_seven_int, err := getN(phone_num[0:7])
+	db, err := Default()
 	if err != nil {
-		return nil, errors.New("illegal phone number")
-	}
-	phone_seven_int32 := int32(phone_seven_int)
-	right := (total_len - firstoffset) / PHONE_INDEX_LENGTH
-	for {
-		if left > right {
-			break
-		}
-		mid := (left + right) / 2
-		offset := firstoffset + mid*PHONE_INDEX_LENGTH
-		if offset >= total_len {
-			break
-		}
-		cur_phone := get4(content[offset : offset+INT_LEN])
-		record_offset := get4(content[offset+INT_LEN : offset+INT_LEN*2])
-		card_type := content[offset+INT_LEN*2 : offset+INT_LEN*2+CHAR_LEN][0]
-		switch {
-		case cur_phone > phone_seven_int32:
-			right = mid - 1
-		case cur_phone < phone_seven_int32:
-			left = mid + 1
-		default:
-			cbyte := content[record_offset:]
-			end_offset := int32(bytes.Index(cbyte, []byte("\000")))
-			data := bytes.Split(cbyte[:end_offset], []byte("|"))
-			card_str, ok := CardTypemap[card_type]
-			if !ok {
-				card_str = "未知电信运营商"
-			}
-			pr = &PhoneRecord{
-				PhoneNum: phone_num,
-				Province: string(data[0]),
-				City:     string(data[1]),
-				ZipCode:  string(data[2]),
-				AreaZone: string(data[3]),
-				CardType: card_str,
-			}
-			return
-		}
+		return nil, err
 	}
-	return nil, errors.New("phone's data not found")
+	return db.Find(phone_num)
 }
@@ -32,6 +32,69 @@ func BenchmarkFindPhone(b *testing.B) {
 
 }
 
+func lookupNumbers() []string {
+	numbers := make([]string, 10001)
+	for i := range numbers {
+		numbers[i] = fmt.Sprintf("%s%04d%s", "1897", i%10000, "456")
+	}
+	return numbers
+}
+
+func BenchmarkLookupPhone(b *testing.B) {
+	numbers := lookupNumbers()
+	db, err := Default()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(p *testing.PB) {
+
+		var i = 0
+		for p.Next() {
+			i++
+			_, err := db.Lookup(numbers[i%len(numbers)])
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+
+	})
+
+}
+
+func TestLookupNoAlloc(t *testing.T) {
+	numbers := lookupNumbers()
+	db, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := 0
+	allocs := testing.AllocsPerRun(1000, func() {
+		i++
+		if _, err := db.Lookup(numbers[i%len(numbers)]); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatal("验证失败", allocs)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	loc1, err := Lookup("18957509123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc2, err := Lookup("18957500000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc1 != loc2 || loc1.City != "绍兴" || loc1.CardType != "中国电信" {
+		t.Fatal("验证失败", loc1, loc2)
+	}
+}
+
 func TestFindPhone1(t *testing.T) {
 
 	_, err := Find("13580198235123123213213")
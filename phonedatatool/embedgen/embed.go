@@ -0,0 +1,95 @@
+package embedgen
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/xluohome/phonedata"
+	"go/format"
+	"go/token"
+	"text/template"
+)
+
+const PhoneDataFileName = "phone.dat" // 生成的包里数据文件的文件名
+
+var sourceTemplate = template.Must(template.New("source").Parse(`// Code generated by phonedatatool; DO NOT EDIT.
+
+// Package {{.Package}} 将版本为 {{.Version}} 的 phone.dat 打包进可执行文件，使用时不需要 PHONE_DATA_DIR。
+package {{.Package}}
+
+import (
+	_ "embed"
+	"sync"
+
+	"github.com/xluohome/phonedata"
+)
+
+// Version 是内嵌数据文件的版本号。
+const Version = {{printf "%q" .Version}}
+
+//go:embed {{.FileName}}
+var content []byte
+
+var (
+	once sync.Once
+	db     *phonedata.DB
+	db_err error
+)
+
+// Load 返回由内嵌数据加载的 DB，第一次调用时加载，之后返回同一个 DB。
+// 内嵌数据在生成时已经校验过，返回错误说明文件被改动了。
+func Load() (*phonedata.DB, error) {
+	once.Do(func() {
+		db, db_err = phonedata.Load(content)
+	})
+	return db, db_err
+}
+
+// DB 与 Load 相同，但加载失败时 panic，适合在初始化时使用。
+func DB() *phonedata.DB {
+	db, err := Load()
+	if err != nil {
+		panic(err)
+	}
+	return db
+}
+
+// Find 在内嵌数据中查询号码归属地。
+func Find(phone_num string) (*phonedata.PhoneRecord, error) {
+	db, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return db.Find(phone_num)
+}
+
+// SetDefault 让 phonedata.Find 使用内嵌数据，加载失败时返回错误，默认 DB 不变。
+func SetDefault() error {
+	db, err := Load()
+	if err != nil {
+		return err
+	}
+	phonedata.SetDefault(db)
+	return nil
+}
+`))
+
+// Generate 校验 phoneDataBuf，然后生成内嵌该数据文件的 Go 包的源码。
+// 生成的源码需要和名为 PhoneDataFileName 的数据文件放在同一目录。
+func Generate(phoneDataBuf []byte, packageName string) ([]byte, error) {
+	if !token.IsIdentifier(packageName) {
+		return nil, fmt.Errorf("invalid package name %v", packageName)
+	}
+	db, err := phonedata.Load(phoneDataBuf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone data: %v", err)
+	}
+	w := bytes.NewBuffer(nil)
+	if err := sourceTemplate.Execute(w, map[string]string{
+		"Package":  packageName,
+		"Version":  db.Version(),
+		"FileName": PhoneDataFileName,
+	}); err != nil {
+		return nil, err
+	}
+	return format.Source(w.Bytes())
+}
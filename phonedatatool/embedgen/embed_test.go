@@ -0,0 +1,43 @@
+package embedgen
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	buf, err := os.ReadFile("../../phone.dat")
+	assert.NoError(t, err)
+	source, err := Generate(buf, "mydata")
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(source), "package mydata\n"))
+	assert.True(t, strings.Contains(string(source), "const Version = \"2108\"\n"))
+	assert.True(t, strings.Contains(string(source), "//go:embed phone.dat\n"))
+	assert.True(t, strings.Contains(string(source), "版本为 2108 的 phone.dat"))
+	assert.True(t, strings.Contains(string(source), "func Load() (*phonedata.DB, error) {\n"))
+
+	// 版本号中的引号和反斜杠被转义
+	quoted := append([]byte(nil), buf...)
+	copy(quoted, "2\"\\1")
+	source, err = Generate(quoted, "mydata")
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(source), "const Version = \"2\\\"\\\\1\"\n"))
+
+	_, err = Generate(buf, "my-data")
+	assert.Error(t, err)
+	_, err = Generate([]byte("2108"), "mydata")
+	assert.Error(t, err)
+}
+
+// embedded 包是用 Generate 生成的，修改模板后需要重新生成
+func TestEmbeddedUpToDate(t *testing.T) {
+	buf, err := os.ReadFile("../../embedded/" + PhoneDataFileName)
+	assert.NoError(t, err)
+	source, err := Generate(buf, "embedded")
+	assert.NoError(t, err)
+	generated, err := os.ReadFile("../../embedded/embedded.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(source), string(generated))
+}
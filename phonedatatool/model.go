@@ -1,7 +1,7 @@
 package phonedatatool
 
 import (
-	"github.com/xluohome/phonedata"
+	"github.com/xluohome/phonedata/carrier"
 	"strconv"
 )
 
@@ -21,11 +21,12 @@ func (ctid CardTypeID) String() string {
 	return strconv.Itoa(int(ctid))
 }
 func (ctid CardTypeID) ToName() CardTypeName {
-	if v, ok := phonedata.CardTypemap[byte(ctid)]; ok {
-		return CardTypeName(v)
-	} else {
-		return "---"
-	}
+	return CardTypeName(ctid.ToCarrier().Name)
+}
+
+// ToCarrier 在内置的运营商注册表中查询卡类型对应的结构化运营商信息。
+func (ctid CardTypeID) ToCarrier() carrier.Carrier {
+	return carrier.Default().Of(byte(ctid))
 }
 
 type CardTypeName string // 卡类型中文名
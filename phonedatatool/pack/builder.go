@@ -0,0 +1,111 @@
+package pack
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	MinNumberPrefix NumberPrefix = 1000000 // 号码前 7 位的最小值
+	MaxNumberPrefix NumberPrefix = 9999999 // 号码前 7 位的最大值
+)
+
+// Record 是一条归属地记录，对应 record.txt 中的一行（不含记录区 ID）。
+type Record struct {
+	Province string
+	City     string
+	ZipCode  string
+	AreaCode string
+}
+
+func (r Record) item() RecordItem {
+	return RecordItem{province: r.Province, city: r.City, zipCode: r.ZipCode, areaCode: r.AreaCode}
+}
+
+func (r Record) validate() error {
+	for _, v := range []string{r.Province, r.City, r.ZipCode, r.AreaCode} {
+		if strings.ContainsAny(v, "|\x00\n") {
+			return fmt.Errorf("invalid record %v: field %q contains '|', '\\0' or '\\n'", r, v)
+		}
+	}
+	return nil
+}
+
+// Builder 在 Go 程序中直接生成二进制文件，不需要先写出文本文件：
+//
+//	pack.NewBuilder().SetVersion("2410").Add(1300000, pack.Record{...}, carrier.CUCC).WriteTo(w)
+//
+// 相同的记录只保存一份，按第一次加入的顺序排列。同一个号码前缀加入两次时，
+// 记录和卡类型都相同则忽略，否则报错。出错后的调用都被忽略，错误由 Err、Bytes 或 WriteTo 返回。
+// 需要修改已有数据时使用 Dataset。
+type Builder struct {
+	dataset *Dataset
+	err     error
+}
+
+func NewBuilder() *Builder {
+	return &Builder{dataset: newEmptyDataset()}
+}
+
+// SetVersion 设置版本号，必须是 4 个字符，如 "2410"。
+func (b *Builder) SetVersion(version string) *Builder {
+	if b.err == nil {
+		b.err = b.dataset.SetVersion(version)
+	}
+	return b
+}
+
+// Add 加入号码前 7 位为 prefix 的号段，归属地为 record，卡类型为 cardType，如 carrier.CMCC。
+func (b *Builder) Add(prefix NumberPrefix, record Record, cardType byte) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if e, ok := b.dataset.Lookup(prefix); ok {
+		if e.Record != record || e.CardType != cardType {
+			b.err = fmt.Errorf("conflicting number prefix %v: %v(%v) and %v(%v)", prefix, e.Record, e.CardType, record, cardType)
+		}
+		return b
+	}
+	b.err = b.dataset.Set(prefix, record, cardType)
+	return b
+}
+
+// AddService 加入一条服务号码，如 "10086", "中国移动客服", "运营商"。号码不能重复。
+func (b *Builder) AddService(number, name, category string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.dataset.Service(number); ok {
+		b.err = fmt.Errorf("duplicate service number %v", number)
+		return b
+	}
+	b.err = b.dataset.SetService(number, name, category)
+	return b
+}
+
+// Err 返回第一个错误。
+func (b *Builder) Err() error {
+	return b.err
+}
+
+// Bytes 生成二进制文件的内容。
+func (b *Builder) Bytes() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.dataset.Version() == "" {
+		return nil, fmt.Errorf("no version, call SetVersion first")
+	}
+	return b.dataset.Bytes(), nil
+}
+
+// WriteTo 把二进制文件的内容写入 w。
+func (b *Builder) WriteTo(w io.Writer) (int64, error) {
+	buf, err := b.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
@@ -0,0 +1,81 @@
+package pack
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/xluohome/phonedata"
+	"github.com/xluohome/phonedata/carrier"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	hangzhou := Record{Province: "浙江", City: "杭州", ZipCode: "310000", AreaCode: "0571"}
+	shaoxing := Record{Province: "浙江", City: "绍兴", ZipCode: "312000", AreaCode: "0575"}
+
+	w := bytes.NewBuffer(nil)
+	n, err := NewBuilder().
+		SetVersion("2410").
+		Add(1895750, shaoxing, carrier.CTCC).
+		Add(1300000, hangzhou, carrier.CUCC).
+		Add(1300001, hangzhou, carrier.CUCC).
+		Add(1300001, hangzhou, carrier.CUCC).
+		Add(1700000, hangzhou, carrier.CTCC_v).
+		AddService("10086", "中国移动客服", "运营商").
+		WriteTo(w)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(w.Len()), n)
+
+	// 与打包等价的文本文件的结果完全相同，相同的记录只保存一份
+	expected, err := NewPacker().PackWithService(
+		[]byte("2410"),
+		[]byte("1|浙江|绍兴|312000|0575\n2|浙江|杭州|310000|0571\n"),
+		[]byte("1300000|2|2\n1300001|2|2\n1700000|2|4\n1895750|1|3\n"),
+		[]byte("10086|中国移动客服|运营商\n"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, w.Bytes())
+
+	db, err := phonedata.Load(w.Bytes())
+	assert.NoError(t, err)
+	pr, err := db.Find("18957509123")
+	assert.NoError(t, err)
+	assert.Equal(t, "绍兴", pr.City)
+	assert.Equal(t, "中国电信", pr.CardType)
+	pr, err = db.Find("17000001234")
+	assert.NoError(t, err)
+	assert.Equal(t, "杭州", pr.City)
+	assert.True(t, pr.Carrier.MVNO)
+	sr, err := db.FindService("10086")
+	assert.NoError(t, err)
+	assert.Equal(t, "中国移动客服", sr.Name)
+}
+
+func TestBuilder_Error(t *testing.T) {
+	record := Record{Province: "浙江", City: "杭州", ZipCode: "310000", AreaCode: "0571"}
+	other := Record{Province: "浙江", City: "绍兴", ZipCode: "312000", AreaCode: "0575"}
+
+	for name, builder := range map[string]*Builder{
+		"no version":       NewBuilder().Add(1300000, record, carrier.CUCC),
+		"bad version":      NewBuilder().SetVersion("24100"),
+		"small prefix":     NewBuilder().SetVersion("2410").Add(130000, record, carrier.CUCC),
+		"large prefix":     NewBuilder().SetVersion("2410").Add(13000000, record, carrier.CUCC),
+		"zero card type":   NewBuilder().SetVersion("2410").Add(1300000, record, 0),
+		"bad field":        NewBuilder().SetVersion("2410").Add(1300000, Record{Province: "浙|江"}, carrier.CUCC),
+		"conflict record":  NewBuilder().SetVersion("2410").Add(1300000, record, carrier.CUCC).Add(1300000, other, carrier.CUCC),
+		"conflict carrier": NewBuilder().SetVersion("2410").Add(1300000, record, carrier.CUCC).Add(1300000, record, carrier.CMCC),
+		"bad service":      NewBuilder().SetVersion("2410").AddService("1008a", "a", "b"),
+		"dup service":      NewBuilder().SetVersion("2410").AddService("10086", "a", "b").AddService("10086", "a", "b"),
+	} {
+		_, err := builder.Bytes()
+		assert.Error(t, err, name)
+		_, err = builder.WriteTo(bytes.NewBuffer(nil))
+		assert.Error(t, err, name)
+	}
+
+	builder := NewBuilder().SetVersion("2410").Add(1300000, record, carrier.CUCC).Add(1300000, other, carrier.CUCC)
+	assert.Error(t, builder.Err())
+	// 出错后的调用被忽略，保留第一个错误
+	err := builder.Err()
+	builder.SetVersion("x")
+	assert.Equal(t, err, builder.Err())
+}
@@ -0,0 +1,67 @@
+package pack
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/xluohome/phonedata"
+	"github.com/xluohome/phonedata/carrier"
+	"testing"
+)
+
+func TestPack_CBN(t *testing.T) {
+	versionBuf := []byte("2410")
+	recordBuf := []byte("1|北京|北京|100000|010\n")
+	indexBuf := []byte("1920000|1|7\n1920001|1|8\n")
+
+	buf, err := NewPacker().Pack(versionBuf, recordBuf, indexBuf)
+	assert.NoError(t, err)
+
+	db, err := phonedata.Load(buf)
+	assert.NoError(t, err)
+	pr, err := db.Find("19200001234")
+	assert.NoError(t, err)
+	assert.Equal(t, "中国广电", pr.CardType)
+	assert.Equal(t, "CBN", pr.Carrier.Code)
+	pr, err = db.Find("19200011234")
+	assert.NoError(t, err)
+	assert.Equal(t, "CBN_V", pr.Carrier.Code)
+	assert.True(t, pr.Carrier.MVNO)
+	assert.Equal(t, "CBN", pr.Carrier.Network)
+
+	result, err := NewQuerier().Query(buf, "19200001234")
+	assert.NoError(t, err)
+	assert.Equal(t, "中国广电", result.CardType)
+
+	_, _, unpackedIndexBuf, err := NewUnpacker().Unpack(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, indexBuf, unpackedIndexBuf)
+}
+
+func TestIndexPart_ParsePlainTextInvalidCardType(t *testing.T) {
+	id2offset := map[RecordID]Offset{1: 8}
+	for _, line := range []string{"1920000|1|256\n", "1920000|1|-1\n", "1920000|1|x\n"} {
+		assert.Error(t, NewIndexPart().ParsePlainText(bytes.NewReader([]byte(line)), id2offset), line)
+	}
+}
+
+func TestPack_CarrierCode(t *testing.T) {
+	versionBuf := []byte("2410")
+	recordBuf := []byte("1|北京|北京|100000|010\n")
+
+	numeric, err := NewPacker().Pack(versionBuf, recordBuf, []byte("1300000|1|2\n1920000|1|8\n"))
+	assert.NoError(t, err)
+	coded, err := NewPacker().Pack(versionBuf, recordBuf, []byte("1300000|1|CUCC\n1920000|1|cbn_v\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, numeric, coded)
+
+	_, err = NewPacker().Pack(versionBuf, recordBuf, []byte("1300000|1|XYZ_V\n"))
+	assert.Error(t, err)
+
+	carriers, err := carrier.Default().With(carrier.Carrier{ID: 9, Code: "XYZ_V", Network: "CMCC", Name: "某虚拟运营商"})
+	assert.NoError(t, err)
+	buf, err := NewPackerWithCarriers(carriers).Pack(versionBuf, recordBuf, []byte("1300000|1|XYZ_V\n"))
+	assert.NoError(t, err)
+	_, _, indexBuf, err := NewUnpacker().Unpack(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1300000|1|9\n"), indexBuf)
+}
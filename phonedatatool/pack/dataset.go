@@ -0,0 +1,356 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/xluohome/phonedata/carrier"
+	"github.com/xluohome/phonedata/phonedatatool"
+	"github.com/xluohome/phonedata/reader"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Entry 是 Dataset 中的一个号段。
+type Entry struct {
+	Prefix   NumberPrefix // 号码前 7 位
+	RecordID RecordID     // 记录区 ID，即 record.txt 中的第一列
+	Record   Record
+	CardType byte // 卡类型，如 carrier.CMCC
+}
+
+type datasetEntry struct {
+	recordID   RecordID
+	cardTypeID phonedatatool.CardTypeID
+}
+
+// Dataset 是可以修改的完整数据：版本号、记录、号段和服务号码。
+// 它可以从二进制文件或文本文件加载，修改后再生成两种格式中的任意一种，
+// 用来代替直接编辑 index.txt。Dataset 不能在多个 goroutine 中同时修改。
+type Dataset struct {
+	version     string
+	records     map[RecordID]RecordItem
+	order       []RecordID              // 记录在记录区中的顺序，与加载时的文件相同，新增的记录排在最后
+	record2id   map[RecordItem]RecordID // 内容 -> 排在最前的记录区 ID，用于 Set 时复用记录
+	maxID       RecordID
+	entries     map[NumberPrefix]datasetEntry
+	servicePart *ServicePart
+}
+
+// NewDataset 创建版本号为 version 的空 Dataset。
+func NewDataset(version string) (*Dataset, error) {
+	d := newEmptyDataset()
+	if err := d.SetVersion(version); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// newEmptyDataset 创建没有版本号的空 Dataset。
+func newEmptyDataset() *Dataset {
+	return &Dataset{
+		records:     make(map[RecordID]RecordItem),
+		record2id:   make(map[RecordItem]RecordID),
+		entries:     make(map[NumberPrefix]datasetEntry),
+		servicePart: NewServicePart(),
+	}
+}
+
+// LoadDataset 从二进制文件的内容加载 Dataset。记录区 ID 按记录在文件中的顺序从 1 开始编号，与解包结果相同。
+func LoadDataset(phoneDataBuf []byte) (*Dataset, error) {
+	var result *unpackResult
+	if v, err := new(Unpacker).unpack(phoneDataBuf); err != nil {
+		return nil, err
+	} else {
+		result = v
+	}
+	return newDataset(result.versionPart, result.recordPart, result.indexPart, result.offset2id, result.servicePart)
+}
+
+// LoadDatasetPlainText 从解包得到的文本文件的内容加载 Dataset，servicePlainTextBuf 可以为空。
+// index.txt 中的运营商代码用 carriers 解析，carriers 为 nil 时使用 carrier.Default()。
+func LoadDatasetPlainText(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, servicePlainTextBuf []byte, carriers *carrier.Registry) (*Dataset, error) {
+	if carriers == nil {
+		carriers = carrier.Default()
+	}
+	versionPart := new(VersionPart)
+	if err := versionPart.ParsePlainText(bytes.NewReader(versionPlainTextBuf)); err != nil {
+		return nil, err
+	}
+
+	recordPart := NewRecordPart()
+	if err := recordPart.ParsePlainText(bytes.NewReader(recordPlainTextBuf)); err != nil {
+		return nil, err
+	}
+	_, id2offset := recordPart.Bytes(RecordPartBaseOffset)
+	offset2id := make(map[Offset]RecordID, len(id2offset))
+	for id, offset := range id2offset {
+		offset2id[offset] = id
+	}
+
+	indexPart := NewIndexPartWithCarriers(carriers)
+	if err := indexPart.ParsePlainText(bytes.NewReader(indexPlainTextBuf), id2offset); err != nil {
+		return nil, err
+	}
+
+	servicePart := NewServicePart()
+	if err := servicePart.ParsePlainText(bytes.NewReader(servicePlainTextBuf)); err != nil {
+		return nil, err
+	}
+	return newDataset(versionPart, recordPart, indexPart, offset2id, servicePart)
+}
+
+func newDataset(versionPart *VersionPart, recordPart *RecordPart, indexPart *IndexPart, offset2id map[Offset]RecordID, servicePart *ServicePart) (*Dataset, error) {
+	d, err := NewDataset(versionPart.version)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range recordPart.ids() {
+		d.addRecord(id, *recordPart.id2item[id])
+	}
+	for prefix, item := range indexPart.prefix2item {
+		if id, ok := offset2id[item.recordOffset]; !ok {
+			return nil, fmt.Errorf("number prefix %v points to invalid record offset %v", prefix, item.recordOffset)
+		} else {
+			d.entries[prefix] = datasetEntry{recordID: id, cardTypeID: item.cardTypeID}
+		}
+	}
+	d.servicePart = servicePart
+	return d, nil
+}
+
+// addRecord 把记录 id 加在记录区的最后。
+func (d *Dataset) addRecord(id RecordID, item RecordItem) {
+	d.records[id] = item
+	d.order = append(d.order, id)
+	if id > d.maxID {
+		d.maxID = id
+	}
+	if _, ok := d.record2id[item]; !ok {
+		d.record2id[item] = id
+	}
+}
+
+// Version 返回版本号。
+func (d *Dataset) Version() string {
+	return d.version
+}
+
+// SetVersion 设置版本号，必须是 4 个字符，如 "2410"。
+func (d *Dataset) SetVersion(version string) error {
+	if len(version) != 4 {
+		return fmt.Errorf("invalid version %q: expect 4 bytes", version)
+	}
+	d.version = version
+	return nil
+}
+
+// Len 返回号段的个数。
+func (d *Dataset) Len() int {
+	return len(d.entries)
+}
+
+// RecordCount 返回记录的条数，包括没有号段引用的记录。
+func (d *Dataset) RecordCount() int {
+	return len(d.records)
+}
+
+func (d *Dataset) entry(prefix NumberPrefix, e datasetEntry) Entry {
+	item := d.records[e.recordID]
+	return Entry{
+		Prefix:   prefix,
+		RecordID: e.recordID,
+		Record:   Record{Province: item.province, City: item.city, ZipCode: item.zipCode, AreaCode: item.areaCode},
+		CardType: byte(e.cardTypeID),
+	}
+}
+
+// Lookup 返回号码前 7 位为 prefix 的号段。
+func (d *Dataset) Lookup(prefix NumberPrefix) (Entry, bool) {
+	if e, ok := d.entries[prefix]; ok {
+		return d.entry(prefix, e), true
+	}
+	return Entry{}, false
+}
+
+// Each 按号码前 7 位升序对每个号段调用 fn，fn 返回 false 时停止。fn 中不能修改 Dataset。
+func (d *Dataset) Each(fn func(e Entry) bool) {
+	var prefixList NumberPrefixList
+	for prefix := range d.entries {
+		prefixList = append(prefixList, prefix)
+	}
+	sort.Sort(prefixList)
+	for _, prefix := range prefixList {
+		if !fn(d.entry(prefix, d.entries[prefix])) {
+			return
+		}
+	}
+}
+
+// Record 返回记录区 ID 为 id 的记录。
+func (d *Dataset) Record(id RecordID) (Record, bool) {
+	if item, ok := d.records[id]; ok {
+		return Record{Province: item.province, City: item.city, ZipCode: item.zipCode, AreaCode: item.areaCode}, true
+	}
+	return Record{}, false
+}
+
+// Set 设置号码前 7 位为 prefix 的号段，已存在时覆盖。
+// 已有内容相同的记录时复用该记录，否则在记录区最后新增一条记录，ID 为当前最大 ID + 1。
+func (d *Dataset) Set(prefix NumberPrefix, record Record, cardType byte) error {
+	if prefix < MinNumberPrefix || prefix > MaxNumberPrefix {
+		return fmt.Errorf("invalid number prefix %v", prefix)
+	}
+	if cardType == 0 {
+		return fmt.Errorf("invalid card type id 0 for number prefix %v", prefix)
+	}
+	if err := record.validate(); err != nil {
+		return err
+	}
+	item := record.item()
+	id, ok := d.record2id[item]
+	if !ok {
+		id = d.maxID + 1
+		d.addRecord(id, item)
+	}
+	d.entries[prefix] = datasetEntry{recordID: id, cardTypeID: phonedatatool.CardTypeID(cardType)}
+	return nil
+}
+
+// Delete 删除号码前 7 位为 prefix 的号段，返回号段是否存在。被引用的记录保留，见 Compact。
+func (d *Dataset) Delete(prefix NumberPrefix) bool {
+	if _, ok := d.entries[prefix]; !ok {
+		return false
+	}
+	delete(d.entries, prefix)
+	return true
+}
+
+// Compact 删除没有号段引用的记录，并合并内容相同的记录，剩余的记录按在记录区中的顺序从 1 开始重新编号。
+// 返回删除的记录条数。
+func (d *Dataset) Compact() int {
+	used := make(map[RecordID]bool)
+	for prefix, e := range d.entries {
+		// 内容相同的记录统一使用排在最前的一条
+		e.recordID = d.record2id[d.records[e.recordID]]
+		d.entries[prefix] = e
+		used[e.recordID] = true
+	}
+	var idList []RecordID
+	for _, id := range d.order {
+		if used[id] {
+			idList = append(idList, id)
+		}
+	}
+
+	removed := len(d.records) - len(idList)
+	renumber := make(map[RecordID]RecordID, len(idList))
+	records := d.records
+	d.records = make(map[RecordID]RecordItem, len(idList))
+	d.record2id = make(map[RecordItem]RecordID, len(idList))
+	d.order = make([]RecordID, 0, len(idList))
+	d.maxID = 0
+	for i, id := range idList {
+		renumber[id] = RecordID(i + 1)
+		d.addRecord(RecordID(i+1), records[id])
+	}
+	for prefix, e := range d.entries {
+		e.recordID = renumber[e.recordID]
+		d.entries[prefix] = e
+	}
+	return removed
+}
+
+// Services 返回全部服务号码，按加入的顺序排列。
+func (d *Dataset) Services() []reader.ServiceRecord {
+	services := make([]reader.ServiceRecord, 0, len(d.servicePart.items))
+	for _, item := range d.servicePart.items {
+		services = append(services, reader.ServiceRecord{Number: item.number, Name: item.name, Category: item.category})
+	}
+	return services
+}
+
+// Service 返回号码为 number 的服务号码。
+func (d *Dataset) Service(number string) (reader.ServiceRecord, bool) {
+	for _, item := range d.servicePart.items {
+		if item.number == number {
+			return reader.ServiceRecord{Number: item.number, Name: item.name, Category: item.category}, true
+		}
+	}
+	return reader.ServiceRecord{}, false
+}
+
+// SetService 设置一条服务号码，已存在时覆盖名称和类别。
+func (d *Dataset) SetService(number, name, category string) error {
+	item := new(ServiceItem)
+	if err := item.parseWords([]string{number, name, category}); err != nil {
+		return err
+	}
+	for _, v := range []string{name, category} {
+		if strings.ContainsAny(v, "|\x00\n") {
+			return fmt.Errorf("invalid service %v: %q contains '|', '\\0' or '\\n'", number, v)
+		}
+	}
+	for _, v := range d.servicePart.items {
+		if v.number == number {
+			*v = *item
+			return nil
+		}
+	}
+	return d.servicePart.add(item)
+}
+
+// DeleteService 删除一条服务号码，返回它是否存在。
+func (d *Dataset) DeleteService(number string) bool {
+	for i, v := range d.servicePart.items {
+		if v.number == number {
+			d.servicePart.items = append(d.servicePart.items[:i], d.servicePart.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// parts 生成各部分，记录按在记录区中的顺序排列，与 RecordPart 相同。
+func (d *Dataset) parts() (versionPart *VersionPart, recordPart *RecordPart, indexPart *IndexPart, id2offset map[RecordID]Offset) {
+	versionPart = &VersionPart{version: d.version}
+	recordPart = NewRecordPart()
+	for id, item := range d.records {
+		item := item
+		recordPart.id2item[id] = &item
+	}
+	recordPart.order = append([]RecordID(nil), d.order...)
+	_, id2offset = recordPart.Bytes(RecordPartBaseOffset)
+	indexPart = NewIndexPart()
+	for prefix, e := range d.entries {
+		indexPart.prefix2item[prefix] = &IndexItem{
+			numberPrefix: prefix,
+			recordOffset: id2offset[e.recordID],
+			cardTypeID:   e.cardTypeID,
+		}
+	}
+	return
+}
+
+// Bytes 生成二进制文件的内容。
+func (d *Dataset) Bytes() []byte {
+	versionPart, recordPart, indexPart, _ := d.parts()
+	recordPartBuf, _ := recordPart.Bytes(RecordPartBaseOffset)
+	return assemble(versionPart.Bytes(), recordPartBuf, d.servicePart.Bytes(), indexPart.Bytes())
+}
+
+// WriteTo 把二进制文件的内容写入 w。
+func (d *Dataset) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(d.Bytes())
+	return int64(n), err
+}
+
+// PlainText 生成与解包结果格式相同的文本文件的内容。
+func (d *Dataset) PlainText() (versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, servicePlainTextBuf []byte) {
+	versionPart, recordPart, indexPart, id2offset := d.parts()
+	offset2id := make(map[Offset]RecordID, len(id2offset))
+	for id, offset := range id2offset {
+		offset2id[offset] = id
+	}
+	return versionPart.BytesPlainText(), recordPart.BytesPlainText(), indexPart.BytesPlainText(offset2id), d.servicePart.BytesPlainText()
+}
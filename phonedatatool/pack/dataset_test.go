@@ -0,0 +1,146 @@
+package pack
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/xluohome/phonedata"
+	"github.com/xluohome/phonedata/carrier"
+	"os"
+	"testing"
+)
+
+func TestLoadDataset(t *testing.T) {
+	buf, err := os.ReadFile("../../phone.dat")
+	assert.NoError(t, err)
+	dataset, err := LoadDataset(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "2108", dataset.Version())
+	assert.Equal(t, 454336, dataset.Len())
+
+	entry, ok := dataset.Lookup(1895750)
+	assert.True(t, ok)
+	assert.Equal(t, Record{Province: "浙江", City: "绍兴", ZipCode: "312000", AreaCode: "0575"}, entry.Record)
+	assert.Equal(t, carrier.CTCC, entry.CardType)
+	record, ok := dataset.Record(entry.RecordID)
+	assert.True(t, ok)
+	assert.Equal(t, entry.Record, record)
+	_, ok = dataset.Lookup(1000000)
+	assert.False(t, ok)
+
+	// 两种格式的输出都与解包、原文件相同
+	assert.Equal(t, buf, dataset.Bytes())
+	v, r, i, s, err := NewUnpacker().UnpackWithService(buf)
+	assert.NoError(t, err)
+	v2, r2, i2, s2 := dataset.PlainText()
+	assert.Equal(t, v, v2)
+	assert.Equal(t, r, r2)
+	assert.Equal(t, i, i2)
+	assert.Equal(t, s, s2)
+
+	fromPlainText, err := LoadDatasetPlainText(v, r, i, s, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, buf, fromPlainText.Bytes())
+
+	var prev NumberPrefix
+	count := 0
+	dataset.Each(func(e Entry) bool {
+		assert.True(t, e.Prefix > prev)
+		prev = e.Prefix
+		count++
+		return count < 10
+	})
+	assert.Equal(t, 10, count)
+}
+
+func TestDataset_Modify(t *testing.T) {
+	hangzhou := Record{Province: "浙江", City: "杭州", ZipCode: "310000", AreaCode: "0571"}
+	shaoxing := Record{Province: "浙江", City: "绍兴", ZipCode: "312000", AreaCode: "0575"}
+	newCity := Record{Province: "浙江", City: "新城", ZipCode: "310001", AreaCode: "0571"}
+
+	dataset, err := LoadDatasetPlainText(
+		[]byte("2306\n"),
+		[]byte("1|浙江|杭州|310000|0571\n3|浙江|绍兴|312000|0575\n5|浙江|杭州|310000|0571\n"),
+		[]byte("1300000|1|2\n1300001|3|2\n1300002|5|CUCC\n"),
+		nil,
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, dataset.RecordCount())
+
+	// 覆盖已有号段，复用内容相同的记录
+	assert.NoError(t, dataset.Set(1300001, hangzhou, carrier.CTCC))
+	entry, _ := dataset.Lookup(1300001)
+	assert.Equal(t, RecordID(1), entry.RecordID)
+	assert.Equal(t, carrier.CTCC, entry.CardType)
+
+	// 新的记录 ID 为最大 ID + 1
+	assert.NoError(t, dataset.Set(1990000, newCity, carrier.CBN))
+	entry, _ = dataset.Lookup(1990000)
+	assert.Equal(t, RecordID(6), entry.RecordID)
+
+	assert.True(t, dataset.Delete(1300000))
+	assert.False(t, dataset.Delete(1300000))
+	assert.NoError(t, dataset.SetVersion("2410"))
+	assert.NoError(t, dataset.SetService("10086", "中国移动客服", "运营商"))
+	assert.NoError(t, dataset.SetService("10086", "中国移动", "运营商"))
+	assert.NoError(t, dataset.SetService("110", "报警", "紧急"))
+	assert.True(t, dataset.DeleteService("110"))
+	assert.False(t, dataset.DeleteService("110"))
+
+	// 记录 3（绍兴）没有号段引用，记录 5 与记录 1 相同
+	assert.Equal(t, 2, dataset.Compact())
+	assert.Equal(t, 2, dataset.RecordCount())
+	_, ok := dataset.Record(3)
+	assert.False(t, ok)
+
+	v, r, i, s := dataset.PlainText()
+	assert.Equal(t, []byte("2410\n"), v)
+	assert.Equal(t, []byte("1|浙江|杭州|310000|0571\n2|浙江|新城|310001|0571\n"), r)
+	assert.Equal(t, []byte("1300001|1|3\n1300002|1|2\n1990000|2|7\n"), i)
+	assert.Equal(t, []byte("10086|中国移动|运营商\n"), s)
+
+	w := bytes.NewBuffer(nil)
+	_, err = dataset.WriteTo(w)
+	assert.NoError(t, err)
+	db, err := phonedata.Load(w.Bytes())
+	assert.NoError(t, err)
+	pr, err := db.Find("19900001234")
+	assert.NoError(t, err)
+	assert.Equal(t, "新城", pr.City)
+	assert.Equal(t, "CBN", pr.Carrier.Code)
+	_, err = db.Find("13000001234")
+	assert.Error(t, err)
+	_, err = db.Find(shaoxing.AreaCode + "12345678")
+	assert.Error(t, err)
+
+	assert.Error(t, dataset.Set(130000, hangzhou, carrier.CMCC))
+	assert.Error(t, dataset.Set(1300000, hangzhou, 0))
+	assert.Error(t, dataset.Set(1300000, Record{City: "a|b"}, carrier.CMCC))
+	assert.Error(t, dataset.SetVersion("24100"))
+	assert.Error(t, dataset.SetService("1008a", "a", "b"))
+	_, err = NewDataset("")
+	assert.Error(t, err)
+}
+
+func TestDataset_KeepOrder(t *testing.T) {
+	// 记录区 ID 不按升序排列时，记录仍按 record.txt 中的顺序生成，与 Packer 相同
+	v := []byte("2306\n")
+	r := []byte("2|浙江|杭州|310000|0571\n1|浙江|绍兴|312000|0575\n")
+	i := []byte("1300000|1|2\n1300001|2|3\n")
+	dataset, err := LoadDatasetPlainText(v, r, i, nil, nil)
+	assert.NoError(t, err)
+	packed, err := NewPacker().Pack(v, r, i)
+	assert.NoError(t, err)
+	assert.Equal(t, packed, dataset.Bytes())
+	_, r2, i2, _ := dataset.PlainText()
+	assert.Equal(t, r, r2)
+	assert.Equal(t, i, i2)
+
+	// 新增的记录排在最后，Compact 按记录区中的顺序重新编号
+	assert.NoError(t, dataset.Set(1300002, Record{Province: "浙江", City: "新城", ZipCode: "310001", AreaCode: "0571"}, carrier.CMCC))
+	assert.True(t, dataset.Delete(1300001))
+	assert.Equal(t, 1, dataset.Compact())
+	_, r2, i2, _ = dataset.PlainText()
+	assert.Equal(t, []byte("1|浙江|绍兴|312000|0575\n2|浙江|新城|310001|0571\n"), r2)
+	assert.Equal(t, []byte("1300000|1|2\n1300002|2|1\n"), i2)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"github.com/xluohome/phonedata/carrier"
 	"github.com/xluohome/phonedata/phonedatatool"
 	"github.com/xluohome/phonedata/phonedatatool/util"
 	"sort"
@@ -45,24 +46,21 @@ func (ii IndexItem) Bytes() []byte {
 	w.Write(ii.cardTypeID.Bytes())
 	return w.Bytes()
 }
-func (ii *IndexItem) Parse(reader *bytes.Reader) error {
-	buf := make([]byte, 9)
-	if _, err := reader.Read(buf); err != nil {
-		return err
-	}
-	ii.numberPrefix = NumberPrefix(binary.LittleEndian.Uint32(buf[:4]))
-	ii.recordOffset = Offset(binary.LittleEndian.Uint32(buf[4:8]))
-	ii.cardTypeID = phonedatatool.CardTypeID(buf[8])
-	return nil
-}
 
 type IndexPart struct {
 	prefix2item map[NumberPrefix]*IndexItem
+	carriers    *carrier.Registry // 解析纯文本中的运营商代码
 }
 
 func NewIndexPart() *IndexPart {
+	return NewIndexPartWithCarriers(carrier.Default())
+}
+
+// NewIndexPartWithCarriers 创建 IndexPart，纯文本中的卡类型可以是数字，也可以是 carriers 中的运营商代码。
+func NewIndexPartWithCarriers(carriers *carrier.Registry) *IndexPart {
 	return &IndexPart{
 		prefix2item: make(map[NumberPrefix]*IndexItem),
+		carriers:    carriers,
 	}
 }
 
@@ -96,10 +94,10 @@ func (p *IndexPart) ParsePlainText(reader *bytes.Reader, id2offset map[RecordID]
 		}
 
 		var cardTypeID phonedatatool.CardTypeID
-		if v, err := strconv.Atoi(words[2]); err != nil {
-			return fmt.Errorf("invalid card type id %v: %v", words[2], err)
+		if c, err := p.carriers.Parse(words[2]); err != nil {
+			return err
 		} else {
-			cardTypeID = phonedatatool.CardTypeID(v)
+			cardTypeID = phonedatatool.CardTypeID(c.ID)
 		}
 
 		p.prefix2item[numberPrefix] = &IndexItem{
@@ -125,17 +123,6 @@ func (p *IndexPart) Bytes() []byte {
 	return w.Bytes()
 }
 
-func (p *IndexPart) Parse(reader *bytes.Reader) error {
-	for reader.Len() > 0 {
-		item := new(IndexItem)
-		if err := item.Parse(reader); err != nil {
-			return err
-		}
-		p.prefix2item[item.numberPrefix] = item
-	}
-	return nil
-}
-
 func (p *IndexPart) BytesPlainText(offset2id map[Offset]RecordID) []byte {
 	w := bytes.NewBuffer(nil)
 	var prefixList NumberPrefixList
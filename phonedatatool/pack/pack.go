@@ -2,18 +2,30 @@ package pack
 
 import (
 	"bytes"
+	"github.com/xluohome/phonedata/carrier"
 	"github.com/xluohome/phonedata/phonedatatool"
 )
 
-type Packer struct{}
+type Packer struct {
+	carriers *carrier.Registry
+}
 
 const RecordPartBaseOffset = Offset(8) // record part 首字节偏移量
 
 func NewPacker() phonedatatool.Packer {
-	return new(Packer)
+	return NewPackerWithCarriers(carrier.Default())
+}
+
+// NewPackerWithCarriers 创建 Packer，index.txt 中的卡类型可以使用 carriers 中的运营商代码，如 CMCC。
+func NewPackerWithCarriers(carriers *carrier.Registry) phonedatatool.Packer {
+	return &Packer{carriers: carriers}
 }
 
 func (p *Packer) Pack(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf []byte) ([]byte, error) {
+	return p.PackWithService(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, nil)
+}
+
+func (p *Packer) PackWithService(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, servicePlainTextBuf []byte) ([]byte, error) {
 	versionPart := new(VersionPart)
 	if err := versionPart.ParsePlainText(bytes.NewReader(versionPlainTextBuf)); err != nil {
 		return nil, err
@@ -26,26 +38,31 @@ func (p *Packer) Pack(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf
 	}
 	recordPartBuf, recordID2Offset := recordPart.Bytes(RecordPartBaseOffset)
 
-	indexPartOffsetPart := RecordPartBaseOffset + Offset(len(recordPartBuf))
+	servicePart := NewServicePart()
+	if err := servicePart.ParsePlainText(bytes.NewReader(servicePlainTextBuf)); err != nil {
+		return nil, err
+	}
+	servicePartBuf := servicePart.Bytes()
 
-	indexPart := NewIndexPart()
+	indexPart := NewIndexPartWithCarriers(p.carriers)
 	if err := indexPart.ParsePlainText(bytes.NewReader(indexPlainTextBuf), recordID2Offset); err != nil {
 		return nil, err
 	}
 	indexPartBuf := indexPart.Bytes()
 
+	return assemble(versionPartBuf, recordPartBuf, servicePartBuf, indexPartBuf), nil
+}
+
+// assemble 按头部、记录区、服务号码表、索引区的顺序拼接二进制文件。
+// recordPartBuf 必须是以 RecordPartBaseOffset 为起始偏移生成的。
+func assemble(versionPartBuf, recordPartBuf, servicePartBuf, indexPartBuf []byte) []byte {
+	indexPartOffset := RecordPartBaseOffset + Offset(len(recordPartBuf)) + Offset(len(servicePartBuf))
+
 	w := bytes.NewBuffer(nil)
-	if _, err := w.Write(versionPartBuf); err != nil {
-		return nil, err
-	}
-	if _, err := w.Write(indexPartOffsetPart.Bytes()); err != nil {
-		return nil, err
-	}
-	if _, err := w.Write(recordPartBuf); err != nil {
-		return nil, err
-	}
-	if _, err := w.Write(indexPartBuf); err != nil {
-		return nil, err
-	}
-	return w.Bytes(), nil
+	w.Write(versionPartBuf)
+	w.Write(indexPartOffset.Bytes())
+	w.Write(recordPartBuf)
+	w.Write(servicePartBuf)
+	w.Write(indexPartBuf)
+	return w.Bytes()
 }
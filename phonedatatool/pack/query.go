@@ -1,47 +1,59 @@
 package pack
 
 import (
-	"bytes"
 	"fmt"
 	"github.com/xluohome/phonedata/phonedatatool"
-	"strconv"
+	"github.com/xluohome/phonedata/reader"
+	"os"
 )
 
+// Querier 查询二进制文件中的号码。通过 OpenQuerier 或 NewQuerierFromBytes 创建的 Querier
+// 只解析一次文件，之后每次 QueryNumber 在索引区上二分查找，可以在多个 goroutine 中并发使用。
 type Querier struct {
+	rd *reader.Reader
 }
 
+var _ phonedatatool.Querier = (*Querier)(nil)
+
+// NewQuerier 创建不绑定文件的 Querier，只能使用 Query。
 func NewQuerier() *Querier {
 	return &Querier{}
 }
 
-func (q *Querier) Query(phoneDataBuf []byte, number string) (*phonedatatool.QueryResult, error) {
-	if len(number) < 7 {
-		return nil, fmt.Errorf("number %v is too short", number)
+// OpenQuerier 读取 phoneDataFilePath 指向的二进制文件并创建 Querier。
+func OpenQuerier(phoneDataFilePath string, opts ...reader.Option) (*Querier, error) {
+	if buf, err := os.ReadFile(phoneDataFilePath); err != nil {
+		return nil, err
+	} else if q, err := NewQuerierFromBytes(buf, opts...); err != nil {
+		return nil, fmt.Errorf("%v: %v", phoneDataFilePath, err)
+	} else {
+		return q, nil
 	}
-	var numberPrefix NumberPrefix
-	if v, err := strconv.Atoi(number[:7]); err != nil {
+}
+
+// NewQuerierFromBytes 以 phoneDataBuf 作为二进制文件的内容创建 Querier。phoneDataBuf 在此之后不应再被修改。
+func NewQuerierFromBytes(phoneDataBuf []byte, opts ...reader.Option) (*Querier, error) {
+	if rd, err := reader.New(phoneDataBuf, opts...); err != nil {
 		return nil, err
 	} else {
-		numberPrefix = NumberPrefix(v)
+		return &Querier{rd: rd}, nil
 	}
+}
 
-	if result, err := new(Unpacker).unpack(bytes.NewReader(phoneDataBuf)); err != nil {
+// QueryNumber 查询纯数字的号码。7~11 位的号码按前七位查询归属地，13 位的号码按物联网号码查询。
+func (q *Querier) QueryNumber(number string) (*phonedatatool.QueryResult, error) {
+	if q.rd == nil {
+		return nil, fmt.Errorf("querier has no phone data, use OpenQuerier or NewQuerierFromBytes")
+	}
+	return q.rd.Find(number)
+}
+
+// Query 在 phoneDataBuf 中查询一个号码，每次调用都要重新解析文件头和记录区。
+// 查询多个号码时使用 OpenQuerier 或 NewQuerierFromBytes 创建的 Querier 的 QueryNumber。
+func (q *Querier) Query(phoneDataBuf []byte, number string) (*phonedatatool.QueryResult, error) {
+	if q, err := NewQuerierFromBytes(phoneDataBuf); err != nil {
 		return nil, err
 	} else {
-		var indexItem *IndexItem
-		if item, ok := result.indexPart.prefix2item[numberPrefix]; !ok {
-			return nil, fmt.Errorf("unknown prefix of number %v", numberPrefix)
-		} else {
-			indexItem = item
-		}
-		recordItem := result.recordPart.id2item[result.offset2id[indexItem.recordOffset]]
-		return &phonedatatool.QueryResult{
-			PhoneNumber:  phonedatatool.PhoneNumber(number),
-			AreaCode:     phonedatatool.AreaCode(recordItem.areaCode),
-			CardTypeID:   indexItem.cardTypeID,
-			CityName:     phonedatatool.CityName(recordItem.city),
-			ZipCode:      phonedatatool.ZipCode(recordItem.zipCode),
-			ProvinceName: phonedatatool.ProvinceName(recordItem.province),
-		}, nil
+		return q.QueryNumber(number)
 	}
 }
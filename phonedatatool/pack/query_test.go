@@ -0,0 +1,68 @@
+package pack
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/xluohome/phonedata"
+	"github.com/xluohome/phonedata/phonedatatool"
+	"os"
+	"testing"
+)
+
+func TestQuerier_Query(t *testing.T) {
+	buf, err := os.ReadFile("../../phone.dat")
+	assert.NoError(t, err)
+
+	result, err := NewQuerier().Query(buf, "18957509123")
+	assert.NoError(t, err)
+	assert.Equal(t, "0575", result.AreaZone)
+
+	result, err = NewQuerier().Query(buf, "1440123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, phonedata.IoT, result.Type)
+	assert.Equal(t, "中国移动", result.CardType)
+
+	for number, reason := range map[string]error{
+		"1420123456789": phonedata.ErrNotFound,
+		"10648abcdefgh": phonedata.ErrInvalidNumber,
+		"142012345678":  phonedata.ErrInvalidLength,
+		"1300":          phonedata.ErrInvalidLength,
+		"189575091234":  phonedata.ErrInvalidLength,
+		"afsd32323":     phonedata.ErrInvalidNumber,
+		"10074872323":   phonedata.ErrNotFound,
+	} {
+		_, err := NewQuerier().Query(buf, number)
+		assert.True(t, errors.Is(err, reason), number)
+		var lookupErr *phonedata.LookupError
+		assert.True(t, errors.As(err, &lookupErr), number)
+		assert.Equal(t, number, lookupErr.Number)
+	}
+}
+
+func TestQuerier_QueryNumber(t *testing.T) {
+	var querier phonedatatool.Querier
+	q, err := OpenQuerier("../../phone.dat")
+	assert.NoError(t, err)
+	querier = q
+
+	result, err := querier.QueryNumber("18957509123")
+	assert.NoError(t, err)
+	assert.Equal(t, "绍兴", result.City)
+	assert.Equal(t, phonedata.Mobile, result.Type)
+
+	buf, err := os.ReadFile("../../phone.dat")
+	assert.NoError(t, err)
+	for _, number := range []string{"13000001234", "1703576", "1440123456789", "1300", "10074872323"} {
+		expected, expectedErr := NewQuerier().Query(buf, number)
+		actual, actualErr := querier.QueryNumber(number)
+		assert.Equal(t, expected, actual, number)
+		assert.Equal(t, expectedErr, actualErr, number)
+	}
+
+	_, err = OpenQuerier("../../not-exist.dat")
+	assert.Error(t, err)
+	_, err = NewQuerierFromBytes([]byte("2306"))
+	assert.Error(t, err)
+	_, err = NewQuerier().QueryNumber("18957509123")
+	assert.Error(t, err)
+}
@@ -41,25 +41,9 @@ func (ri *RecordItem) Bytes() []byte {
 	return w.Bytes()
 }
 
-// Parse 从压缩文件读取一条 RecordItem。注意 reader 必须以 '\0' 结尾。
-func (ri *RecordItem) Parse(reader *bytes.Reader) error {
-	if buf, err := util.ReadUntil(reader, 0); err != nil {
-		return fmt.Errorf("no term char for record item: %v", err)
-	} else {
-		words := strings.Split(string(buf), "|")
-		if len(words) != 4 {
-			return fmt.Errorf("invalid item bytes, %v", string(buf))
-		}
-		ri.province = words[0]
-		ri.city = words[1]
-		ri.zipCode = words[2]
-		ri.areaCode = words[3]
-		return nil
-	}
-}
-
 type RecordPart struct {
 	id2item map[RecordID]*RecordItem
+	order   []RecordID // 记录在文本文件或二进制文件中的顺序，生成时按此顺序排列以保证原样还原
 }
 
 func NewRecordPart() *RecordPart {
@@ -96,58 +80,38 @@ func (p *RecordPart) ParsePlainText(reader *bytes.Reader) error {
 			zipCode:  words[3],
 			areaCode: words[4],
 		}
+		p.order = append(p.order, recordID)
 	}
 	return nil
 }
 
-func (p *RecordPart) Bytes(baseOffset Offset) ([]byte, map[RecordID]Offset) {
-	w := bytes.NewBuffer(nil)
-	id2offset := make(map[RecordID]Offset)
-
+// ids 返回生成时记录的顺序：有原始顺序时按原始顺序，否则按 ID 升序。
+func (p *RecordPart) ids() []RecordID {
+	if len(p.order) == len(p.id2item) {
+		return p.order
+	}
 	var idList RecordIDList
 	for k := range p.id2item {
 		idList = append(idList, k)
 	}
 	sort.Sort(idList)
+	return idList
+}
+
+func (p *RecordPart) Bytes(baseOffset Offset) ([]byte, map[RecordID]Offset) {
+	w := bytes.NewBuffer(nil)
+	id2offset := make(map[RecordID]Offset)
 
-	for _, id := range idList {
+	for _, id := range p.ids() {
 		id2offset[id] = baseOffset + Offset(w.Len())
 		w.Write(p.id2item[id].Bytes())
 	}
 	return w.Bytes(), id2offset
 }
 
-func (p *RecordPart) Parse(reader *bytes.Reader, baseOffset Offset) (map[Offset]RecordID, error) {
-	offset2id := make(map[Offset]RecordID)
-	offset := baseOffset
-	for id := RecordID(1); reader.Len() > 0; id++ {
-		var itemBuf []byte
-		if buf, err := util.ReadUntil(reader, 0); err != nil {
-			return nil, err
-		} else {
-			itemBuf = buf
-			itemBuf = append(itemBuf, 0)
-		}
-		item := new(RecordItem)
-		if err := item.Parse(bytes.NewReader(itemBuf)); err != nil {
-			return nil, err
-		}
-		offset2id[offset] = id
-		p.id2item[id] = item
-		offset += Offset(len(itemBuf))
-	}
-	return offset2id, nil
-}
-
 func (p *RecordPart) BytesPlainText() []byte {
-	var idList RecordIDList
-	for k := range p.id2item {
-		idList = append(idList, k)
-	}
-	sort.Sort(idList)
-
 	w := bytes.NewBuffer(nil)
-	for _, id := range idList {
+	for _, id := range p.ids() {
 		item := p.id2item[id]
 		w.WriteString(strings.Join([]string{
 			id.String(),
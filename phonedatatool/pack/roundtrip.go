@@ -0,0 +1,60 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/xluohome/phonedata/reader"
+)
+
+// CheckRoundTrip 把二进制文件的内容解包成文本，再打包回二进制，校验得到的内容与 phoneDataBuf 逐字节相同。
+// 不同时返回的错误中包含第一个不同字节的偏移（头部的索引区偏移最后比较）和所在的区域。
+// 记录区中的记录按解包时的顺序原样生成，因此格式正确的文件都应通过校验；
+// 不通过说明文件中有文本格式无法表示的内容，如记录中含有换行符、服务号码表只有标记没有号码。
+func CheckRoundTrip(phoneDataBuf []byte) error {
+	var rd *reader.Reader
+	if r, err := reader.New(phoneDataBuf); err != nil {
+		return err
+	} else {
+		rd = r
+	}
+
+	var repacked []byte
+	if v, r, i, s, err := NewUnpacker().UnpackWithService(phoneDataBuf); err != nil {
+		return fmt.Errorf("unpack: %v", err)
+	} else if buf, err := NewPacker().PackWithService(v, r, i, s); err != nil {
+		return fmt.Errorf("pack: %v", err)
+	} else {
+		repacked = buf
+	}
+
+	if bytes.Equal(phoneDataBuf, repacked) {
+		return nil
+	}
+	// 头部的索引区偏移随记录区长度变化，先找之后第一个不同的字节，更能说明原因
+	offset := reader.HeadLength
+	for offset < len(phoneDataBuf) && offset < len(repacked) && phoneDataBuf[offset] == repacked[offset] {
+		offset++
+	}
+	if offset == len(phoneDataBuf) && offset == len(repacked) {
+		offset = 0
+		for phoneDataBuf[offset] == repacked[offset] {
+			offset++
+		}
+	}
+	return fmt.Errorf("round trip mismatch at offset %d (%v): original %d bytes, repacked %d bytes",
+		offset, roundTripSection(rd, offset), len(phoneDataBuf), len(repacked))
+}
+
+// roundTripSection 返回原文件中偏移 offset 所在的区域。
+func roundTripSection(rd *reader.Reader, offset int) string {
+	switch {
+	case offset < reader.HeadLength:
+		return "header"
+	case offset < int(rd.IndexOffset()):
+		return "record part"
+	case offset < int(rd.Size()):
+		return "index part"
+	default:
+		return "end of file"
+	}
+}
@@ -0,0 +1,51 @@
+package pack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestCheckRoundTrip(t *testing.T) {
+	buf, err := os.ReadFile("../../phone.dat")
+	assert.NoError(t, err)
+	assert.NoError(t, CheckRoundTrip(buf))
+
+	v, r, i, s, err := NewUnpacker().UnpackWithService(buf)
+	assert.NoError(t, err)
+	repacked, err := NewPacker().PackWithService(v, r, i, s)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(buf, repacked))
+}
+
+func TestCheckRoundTrip_Mismatch(t *testing.T) {
+	// 只有服务号码表标记、没有服务号码时，解包得到空的 service.txt，打包后标记丢失
+	w := bytes.NewBuffer(nil)
+	w.WriteString("2410")
+	w.Write(make([]byte, 4))
+	w.WriteString("浙江|杭州|310000|0571\x00")
+	w.WriteString("#service\x00")
+	indexOffset := w.Len()
+	w.Write([]byte{0xE4, 0xD5, 0x13, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01}) // 1300000
+	buf := w.Bytes()
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(indexOffset))
+
+	err := CheckRoundTrip(buf)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "offset 34 (record part)")
+
+	assert.Error(t, CheckRoundTrip([]byte("2410")))
+}
+
+func TestRecordPart_KeepOrder(t *testing.T) {
+	// 记录按 record.txt 中的顺序生成，不按 ID 重新排序
+	plainText := []byte("3|浙江|杭州|310000|0571\n1|浙江|绍兴|312000|0575\n2|安徽|合肥|230000|0551\n")
+	recordPart := NewRecordPart()
+	assert.NoError(t, recordPart.ParsePlainText(bytes.NewReader(plainText)))
+	buf, id2offset := recordPart.Bytes(8)
+	assert.Equal(t, []byte("浙江|杭州|310000|0571\x00浙江|绍兴|312000|0575\x00安徽|合肥|230000|0551\x00"), buf)
+	assert.Equal(t, map[RecordID]Offset{3: 8, 1: 34, 2: 60}, id2offset)
+	assert.Equal(t, plainText, recordPart.BytesPlainText())
+}
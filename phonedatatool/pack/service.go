@@ -0,0 +1,102 @@
+package pack
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/xluohome/phonedata/phonedatatool/util"
+	"github.com/xluohome/phonedata/reader"
+	"strings"
+)
+
+// ServicePartMarker 是记录区中服务号码表的起始标记。服务号码表位于所有记录之后、索引区之前，不被索引区引用。
+// 0.2.0 及以前的 phonedatatool 会把它当作记录解析而无法解包、查询带服务号码表的文件，
+// 需要给旧程序使用的文件打包时不要提供 service.txt。
+const ServicePartMarker = reader.ServiceMarker
+
+type ServiceItem struct {
+	number   string
+	name     string
+	category string
+}
+
+func (si *ServiceItem) Bytes() []byte {
+	w := bytes.NewBuffer(nil)
+	w.WriteString(strings.Join([]string{si.number, si.name, si.category}, "|"))
+	w.WriteByte(0)
+	return w.Bytes()
+}
+
+func (si *ServiceItem) parseWords(words []string) error {
+	if len(words) != 3 {
+		return fmt.Errorf("invalid service line. expect 3 words (number, name, category), got %v words, %v", len(words), words)
+	}
+	if words[0] == "" || strings.Trim(words[0], "0123456789") != "" {
+		return fmt.Errorf("invalid service number %v", words[0])
+	}
+	si.number = words[0]
+	si.name = words[1]
+	si.category = words[2]
+	return nil
+}
+
+// ServicePart 是服务号码表，保持文件中的顺序。
+type ServicePart struct {
+	items []*ServiceItem
+}
+
+func NewServicePart() *ServicePart {
+	return &ServicePart{}
+}
+
+func (p *ServicePart) add(item *ServiceItem) error {
+	for _, v := range p.items {
+		if v.number == item.number {
+			return fmt.Errorf("duplicate service number %v", item.number)
+		}
+	}
+	p.items = append(p.items, item)
+	return nil
+}
+
+// ParsePlainText 从文本文件读取，每行形如 "10086|中国移动客服|运营商"。
+func (p *ServicePart) ParsePlainText(reader *bytes.Reader) error {
+	for reader.Len() > 0 {
+		var words []string
+		if b, err := util.ReadUntil(reader, '\n'); err != nil {
+			return err
+		} else {
+			words = strings.Split(string(b), "|")
+		}
+		item := new(ServiceItem)
+		if err := item.parseWords(words); err != nil {
+			return err
+		}
+		if err := p.add(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bytes 打包成二进制文件里的样子，没有服务号码时为空。
+func (p *ServicePart) Bytes() []byte {
+	if len(p.items) == 0 {
+		return nil
+	}
+	w := bytes.NewBuffer(nil)
+	w.WriteString(ServicePartMarker)
+	w.WriteByte(0)
+	for _, item := range p.items {
+		w.Write(item.Bytes())
+	}
+	return w.Bytes()
+}
+
+func (p *ServicePart) BytesPlainText() []byte {
+	w := bytes.NewBuffer(nil)
+	for _, item := range p.items {
+		w.WriteString(strings.Join([]string{item.number, item.name, item.category}, "|"))
+		w.WriteByte('\n')
+	}
+	return w.Bytes()
+}
@@ -0,0 +1,60 @@
+package pack
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestServicePart_ParsePlainText(t *testing.T) {
+	servicePart := NewServicePart()
+	assert.NoError(t, servicePart.ParsePlainText(bytes.NewReader([]byte("10086|中国移动客服|运营商\n95588|中国工商银行|银行\n"))))
+	assert.Equal(t, []*ServiceItem{
+		{number: "10086", name: "中国移动客服", category: "运营商"},
+		{number: "95588", name: "中国工商银行", category: "银行"},
+	}, servicePart.items)
+
+	assert.Error(t, NewServicePart().ParsePlainText(bytes.NewReader([]byte("10086|中国移动客服\n"))))
+	assert.Error(t, NewServicePart().ParsePlainText(bytes.NewReader([]byte("1008a|中国移动客服|运营商\n"))))
+	assert.Error(t, NewServicePart().ParsePlainText(bytes.NewReader([]byte("10086|a|b\n10086|c|d\n"))))
+}
+
+func TestServicePart_Bytes(t *testing.T) {
+	assert.Nil(t, NewServicePart().Bytes())
+	servicePart := &ServicePart{items: []*ServiceItem{
+		{number: "10086", name: "a", category: "b"},
+		{number: "110", name: "c", category: "d"},
+	}}
+	assert.Equal(t, []byte("#service\x0010086|a|b\x00110|c|d\x00"), servicePart.Bytes())
+}
+
+func TestServicePart_BytesPlainText(t *testing.T) {
+	servicePart := &ServicePart{items: []*ServiceItem{
+		{number: "10086", name: "a", category: "b"},
+		{number: "110", name: "c", category: "d"},
+	}}
+	assert.Equal(t, []byte("10086|a|b\n110|c|d\n"), servicePart.BytesPlainText())
+}
+
+func TestPacker_PackWithService(t *testing.T) {
+	versionBuf := []byte("2306\n")
+	recordBuf := []byte("1|a|b|c|d\n")
+	indexBuf := []byte("1300000|1|2\n")
+	serviceBuf := []byte("10086|中国移动客服|运营商\n")
+	buf, err := NewPacker().PackWithService(versionBuf, recordBuf, indexBuf, serviceBuf)
+	assert.NoError(t, err)
+
+	v, r, i, s, err := NewUnpacker().UnpackWithService(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, versionBuf, v)
+	assert.Equal(t, recordBuf, r)
+	assert.Equal(t, indexBuf, i)
+	assert.Equal(t, serviceBuf, s)
+
+	// 没有服务号码时和 Pack 的结果相同
+	withoutService, err := NewPacker().PackWithService(versionBuf, recordBuf, indexBuf, nil)
+	assert.NoError(t, err)
+	packed, err := NewPacker().Pack(versionBuf, recordBuf, indexBuf)
+	assert.NoError(t, err)
+	assert.Equal(t, packed, withoutService)
+}
@@ -1,8 +1,8 @@
 package pack
 
 import (
-	"bytes"
 	"github.com/xluohome/phonedata/phonedatatool"
+	"github.com/xluohome/phonedata/reader"
 )
 
 type Unpacker struct {
@@ -17,50 +17,75 @@ type unpackResult struct {
 	recordPart  *RecordPart
 	offset2id   map[Offset]RecordID
 	indexPart   *IndexPart
+	servicePart *ServicePart
 }
 
 func (u *Unpacker) Unpack(phoneDataBuf []byte) (versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf []byte, err error) {
-	if result, err := u.unpack(bytes.NewReader(phoneDataBuf)); err != nil {
-		return nil, nil, nil, err
-	} else {
-		return result.versionPart.BytesPlainText(), result.recordPart.BytesPlainText(), result.indexPart.BytesPlainText(result.offset2id), nil
-	}
+	versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, _, err = u.UnpackWithService(phoneDataBuf)
+	return
 }
 
-func (u *Unpacker) unpack(reader *bytes.Reader) (*unpackResult, error) {
-	versionPart := new(VersionPart)
-	if err := versionPart.Parse(reader); err != nil {
-		return nil, err
+func (u *Unpacker) UnpackWithService(phoneDataBuf []byte) (versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, servicePlainTextBuf []byte, err error) {
+	if result, err := u.unpack(phoneDataBuf); err != nil {
+		return nil, nil, nil, nil, err
+	} else {
+		return result.versionPart.BytesPlainText(), result.recordPart.BytesPlainText(), result.indexPart.BytesPlainText(result.offset2id), result.servicePart.BytesPlainText(), nil
 	}
+}
 
-	var indexPartOffset Offset
-	if err := indexPartOffset.Parse(reader); err != nil {
+// unpack 用 reader 解析二进制文件，记录 ID 按记录在文件中的顺序从 1 开始编号。
+func (u *Unpacker) unpack(phoneDataBuf []byte) (*unpackResult, error) {
+	var rd *reader.Reader
+	if r, err := reader.New(phoneDataBuf); err != nil {
 		return nil, err
+	} else {
+		rd = r
 	}
 
-	recordBuf := make([]byte, indexPartOffset-RecordPartBaseOffset)
-	if _, err := reader.Read(recordBuf); err != nil {
-		return nil, err
-	}
+	versionPart := &VersionPart{version: rd.Version()}
 
 	recordPart := NewRecordPart()
 	offset2id := make(map[Offset]RecordID)
-	if o2i, err := recordPart.Parse(bytes.NewReader(recordBuf), RecordPartBaseOffset); err != nil {
-		return nil, err
-	} else {
-		offset2id = o2i
+	for i, record := range rd.Records() {
+		id := RecordID(i + 1)
+		offset2id[Offset(record.Offset)] = id
+		recordPart.id2item[id] = &RecordItem{
+			province: record.Province,
+			city:     record.City,
+			zipCode:  record.ZipCode,
+			areaCode: record.AreaZone,
+		}
+		recordPart.order = append(recordPart.order, id)
+	}
+
+	servicePart := NewServicePart()
+	for _, service := range rd.Services() {
+		if err := servicePart.add(&ServiceItem{
+			number:   service.Number,
+			name:     service.Name,
+			category: service.Category,
+		}); err != nil {
+			return nil, err
+		}
 	}
 
 	indexPart := NewIndexPart()
-	if err := indexPart.Parse(reader); err != nil {
+	if err := rd.Each(func(i int32, e reader.Entry) error {
+		indexPart.prefix2item[NumberPrefix(e.Prefix)] = &IndexItem{
+			numberPrefix: NumberPrefix(e.Prefix),
+			recordOffset: Offset(e.RecordOffset),
+			cardTypeID:   phonedatatool.CardTypeID(e.CardType),
+		}
+		return nil
+	}); err != nil {
 		return nil, err
 	}
-	indexPart.BytesPlainText(offset2id)
 
 	return &unpackResult{
 		versionPart: versionPart,
 		recordPart:  recordPart,
 		offset2id:   offset2id,
 		indexPart:   indexPart,
+		servicePart: servicePart,
 	}, nil
 }
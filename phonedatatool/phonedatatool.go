@@ -1,24 +1,26 @@
 package phonedatatool
 
+import "github.com/xluohome/phonedata/reader"
+
 type Unpacker interface {
 	// Unpack 将二进制文件的内容解包成版本文件、记录文件、索引文件的内容。
 	Unpack(phoneDataBuf []byte) (versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf []byte, err error)
+	// UnpackWithService 和 Unpack 相同，另外解包出服务号码文件的内容。
+	UnpackWithService(phoneDataBuf []byte) (versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, servicePlainTextBuf []byte, err error)
 }
 
 type Packer interface {
 	// Pack 将版本文件、记录文件、索引文件的内容打包成二进制文件的内容。
 	Pack(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf []byte) ([]byte, error)
+	// PackWithService 和 Pack 相同，另外把服务号码文件的内容打包进去。
+	PackWithService(versionPlainTextBuf, recordPlainTextBuf, indexPlainTextBuf, servicePlainTextBuf []byte) ([]byte, error)
 }
 
-type QueryResult struct {
-	PhoneNumber  PhoneNumber
-	AreaCode     AreaCode
-	CardTypeID   CardTypeID
-	CityName     CityName
-	ZipCode      ZipCode
-	ProvinceName ProvinceName
-}
+// QueryResult 是号码查询的结果，与 phonedata.PhoneRecord 相同，见 reader.PhoneRecord。
+// 卡类型 ID 为 Carrier.ID。
+type QueryResult = reader.PhoneRecord
 
 type Querier interface {
+	// QueryNumber 查询一个纯数字的号码。
 	QueryNumber(number string) (*QueryResult, error)
 }
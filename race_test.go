@@ -0,0 +1,7 @@
+//go:build race
+// +build race
+
+package phonedata
+
+// raceEnabled 为 true 时 sync.Pool 会随机丢弃对象，不能检查内存分配次数。
+const raceEnabled = true
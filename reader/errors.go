@@ -0,0 +1,32 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 查询失败的原因，可以用 errors.Is 判断。
+var (
+	ErrInvalidLength = errors.New("illegal phone length")       // 号码长度不合法
+	ErrInvalidNumber = errors.New("illegal phone number")       // 号码含有非数字字符
+	ErrNotFound      = errors.New("phone's data not found")     // 号码格式正确，但数据文件中没有该号段
+	ErrInternational = errors.New("international phone number") // 国外号码
+)
+
+// LookupError 记录查询失败的号码和原因，可以用 errors.As 取出。
+type LookupError struct {
+	Number string // 查询的号码
+	Err    error  // 失败原因，如 ErrInvalidLength、ErrInvalidNumber、ErrNotFound
+}
+
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.Number)
+}
+
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
+
+func lookupError(number string, err error) error {
+	return &LookupError{Number: number, Err: err}
+}
@@ -0,0 +1,65 @@
+package reader
+
+import (
+	"strings"
+
+	"github.com/xluohome/phonedata/carrier"
+)
+
+const IoTPhoneLength = 13 // 物联网号码的长度
+
+// iotSegments 是 13 位物联网号码的号段及其运营商，较长的号段排在前面。
+var iotSegments = []struct {
+	prefix   string
+	cardType byte
+}{
+	{"10648", carrier.CMCC},
+	{"10646", carrier.CUCC},
+	{"10649", carrier.CTCC},
+	{"1440", carrier.CMCC},
+	{"1410", carrier.CTCC},
+	{"148", carrier.CMCC},
+	{"146", carrier.CUCC},
+}
+
+// IoTCardType 返回 13 位物联网号码 number 所属运营商的卡类型。
+// 号码不是 13 位数字、不在已知的物联网号段时 ok 为 false。
+func IoTCardType(number string) (cardType byte, ok bool) {
+	if len(number) != IoTPhoneLength || !isDigits(number) {
+		return 0, false
+	}
+	for _, seg := range iotSegments {
+		if strings.HasPrefix(number, seg.prefix) {
+			return seg.cardType, true
+		}
+	}
+	return 0, false
+}
+
+// FindIoT 查询 13 位物联网号码。物联网号码没有归属地，只根据号段确定运营商，
+// 含有非数字字符时返回 ErrInvalidNumber，未知号段返回 ErrNotFound。错误中记录的号码为 raw。
+func FindIoT(raw, number string, carriers *carrier.Registry) (*PhoneRecord, error) {
+	if !isDigits(number) {
+		return nil, lookupError(raw, ErrInvalidNumber)
+	}
+	cardType, ok := IoTCardType(number)
+	if !ok {
+		return nil, lookupError(raw, ErrNotFound)
+	}
+	c := carriers.Of(cardType)
+	return &PhoneRecord{
+		PhoneNum: number,
+		CardType: c.Name,
+		Carrier:  c,
+		Type:     IoT,
+	}, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,354 @@
+// Package reader 解析 phone.dat 的二进制格式，phonedata 和 phonedatatool 共用。
+//
+// phone.dat 由三部分组成：
+//
+//	头部   4 字节版本号 + 4 字节索引区偏移（小端）
+//	记录区 "<省份>|<城市>|<邮编>|<长途区号>\0" 若干条，之后可以有以 "#service\0" 开始的服务号码表
+//	索引区 每条 9 字节：号码前七位（4 字节）、记录区偏移（4 字节）、卡类型（1 字节），按号码升序
+package reader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/xluohome/phonedata/carrier"
+)
+
+const (
+	VersionLength    = 4
+	HeadLength       = 8
+	IndexEntryLength = 9
+
+	// ServiceMarker 是记录区中服务号码表的起始标记。服务号码表位于所有记录之后、索引区之前，
+	// 每条为 "<号码>|<名称>|<类别>\0"，不被索引区引用。
+	ServiceMarker = "#service"
+)
+
+// Reader 是一份已解析的 phone.dat。头部、记录区和服务号码表在创建时读入内存并校验，
+// 索引区按需读取：底层是 []byte 时直接寻址，是 io.ReaderAt 时每次读取 9 字节。
+// Reader 创建后只读，可以在多个 goroutine 中共用。
+type Reader struct {
+	content     []byte      // 整个文件，NewReaderAt 创建时为 nil
+	ra          io.ReaderAt // content 为 nil 时从这里读取索引区
+	size        int32
+	indexOffset int32
+	head        []byte // 头部和记录区，下标即文件偏移
+
+	records  []Record
+	offsets  map[int32]int // 记录区偏移 -> records 下标
+	services []ServiceRecord
+	carriers *carrier.Registry
+}
+
+// Option 是创建 Reader 时的可选配置。
+type Option func(*Reader)
+
+// WithCarriers 指定解码卡类型使用的运营商注册表，默认为 carrier.Default()。
+func WithCarriers(carriers *carrier.Registry) Option {
+	return func(r *Reader) {
+		if carriers != nil {
+			r.carriers = carriers
+		}
+	}
+}
+
+// New 解析 content 作为 phone.dat 的内容。content 在此之后不应再被修改。
+func New(content []byte, opts ...Option) (*Reader, error) {
+	r := &Reader{content: content}
+	if err := r.init(int64(len(content)), opts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewReaderAt 从 ra 读取长度为 size 的 phone.dat。查询期间 ra 必须保持可读。
+func NewReaderAt(ra io.ReaderAt, size int64, opts ...Option) (*Reader, error) {
+	r := &Reader{ra: ra}
+	if err := r.init(size, opts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reader) init(size int64, opts []Option) error {
+	r.carriers = carrier.Default()
+	for _, opt := range opts {
+		opt(r)
+	}
+	if size < HeadLength {
+		return fmt.Errorf("phone data too short: %d bytes", size)
+	}
+	if size > 1<<31-1 {
+		return fmt.Errorf("phone data too long: %d bytes", size)
+	}
+	r.size = int32(size)
+
+	header := make([]byte, HeadLength)
+	if err := r.readAt(header, 0); err != nil {
+		return err
+	}
+	r.indexOffset = int32(binary.LittleEndian.Uint32(header[VersionLength:HeadLength]))
+	if r.indexOffset < HeadLength || r.indexOffset > r.size {
+		return fmt.Errorf("invalid index offset %d", r.indexOffset)
+	}
+	if (r.size-r.indexOffset)%IndexEntryLength != 0 {
+		return fmt.Errorf("index area length %d is not a multiple of %d", r.size-r.indexOffset, IndexEntryLength)
+	}
+	if r.content != nil {
+		r.head = r.content[:r.indexOffset]
+	} else {
+		r.head = make([]byte, r.indexOffset)
+		if err := r.readAt(r.head, 0); err != nil {
+			return err
+		}
+	}
+	return r.parseRecords()
+}
+
+// parseRecords 解析记录区和服务号码表。
+func (r *Reader) parseRecords() error {
+	r.offsets = make(map[int32]int)
+	numbers := make(map[string]bool)
+	inService := false
+	for offset := int32(HeadLength); offset < r.indexOffset; {
+		start := offset
+		end := bytes.IndexByte(r.head[start:], 0)
+		if end < 0 {
+			return fmt.Errorf("record at offset %d not terminated", start)
+		}
+		offset += int32(end) + 1
+		text := string(r.head[start : start+int32(end)])
+		if !inService && text == ServiceMarker {
+			// 记录之后是服务号码表
+			inService = true
+			continue
+		}
+		if inService {
+			sr, err := parseService(text)
+			if err != nil {
+				return fmt.Errorf("service at offset %d: %v", start, err)
+			}
+			if numbers[sr.Number] {
+				return fmt.Errorf("service at offset %d: duplicate service number", start)
+			}
+			numbers[sr.Number] = true
+			r.services = append(r.services, sr)
+			continue
+		}
+		words := strings.Split(text, "|")
+		if len(words) != 4 {
+			return fmt.Errorf("invalid record at offset %d", start)
+		}
+		r.offsets[start] = len(r.records)
+		r.records = append(r.records, Record{
+			Offset:   start,
+			Province: words[0],
+			City:     words[1],
+			ZipCode:  words[2],
+			AreaZone: words[3],
+		})
+	}
+	return nil
+}
+
+func parseService(text string) (ServiceRecord, error) {
+	words := strings.Split(text, "|")
+	if len(words) != 3 {
+		return ServiceRecord{}, errors.New("invalid service record")
+	}
+	if words[0] == "" || strings.Trim(words[0], "0123456789") != "" {
+		return ServiceRecord{}, errors.New("invalid service number")
+	}
+	return ServiceRecord{Number: words[0], Name: words[1], Category: words[2]}, nil
+}
+
+func (r *Reader) readAt(b []byte, offset int64) error {
+	if r.content != nil {
+		if offset+int64(len(b)) > int64(len(r.content)) {
+			return io.ErrUnexpectedEOF
+		}
+		copy(b, r.content[offset:])
+		return nil
+	}
+	if n, err := r.ra.ReadAt(b, offset); n < len(b) {
+		return err
+	}
+	return nil
+}
+
+// Version 返回数据文件的版本号，如 "2108"。
+func (r *Reader) Version() string {
+	return string(r.head[:VersionLength])
+}
+
+// Size 返回数据文件的长度。
+func (r *Reader) Size() int32 {
+	return r.size
+}
+
+// IndexOffset 返回索引区的起始偏移。
+func (r *Reader) IndexOffset() int32 {
+	return r.indexOffset
+}
+
+// Count 返回索引区中索引的条数。
+func (r *Reader) Count() int32 {
+	return (r.size - r.indexOffset) / IndexEntryLength
+}
+
+// Carriers 返回解码卡类型使用的运营商注册表。
+func (r *Reader) Carriers() *carrier.Registry {
+	return r.carriers
+}
+
+// Records 返回记录区的全部记录，按在文件中的顺序排列。调用方不能修改。
+func (r *Reader) Records() []Record {
+	return r.records
+}
+
+// Record 返回记录区偏移为 offset 的记录。
+func (r *Reader) Record(offset int32) (*Record, bool) {
+	i, ok := r.offsets[offset]
+	if !ok {
+		return nil, false
+	}
+	return &r.records[i], true
+}
+
+// Services 返回服务号码表，按在文件中的顺序排列。没有服务号码表时为空。调用方不能修改。
+func (r *Reader) Services() []ServiceRecord {
+	return r.services
+}
+
+var entryBufs = sync.Pool{New: func() interface{} { return new([IndexEntryLength]byte) }}
+
+// Entry 返回索引区中的第 i 条索引。
+func (r *Reader) Entry(i int32) (Entry, error) {
+	if i < 0 || i >= r.Count() {
+		return Entry{}, fmt.Errorf("index %d out of range", i)
+	}
+	offset := r.indexOffset + i*IndexEntryLength
+	var b []byte
+	if r.content != nil {
+		b = r.content[offset : offset+IndexEntryLength]
+	} else {
+		// buf 传给 io.ReaderAt 后会逃逸到堆上，从 entryBufs 中复用
+		buf := entryBufs.Get().(*[IndexEntryLength]byte)
+		defer entryBufs.Put(buf)
+		if err := r.readAt(buf[:], int64(offset)); err != nil {
+			return Entry{}, err
+		}
+		b = buf[:]
+	}
+	return Entry{
+		Prefix:       int32(binary.LittleEndian.Uint32(b[0:4])),
+		RecordOffset: int32(binary.LittleEndian.Uint32(b[4:8])),
+		CardType:     b[8],
+	}, nil
+}
+
+// Each 按顺序对每一条索引调用 fn，同时校验索引按号码严格升序、记录区偏移指向一条记录。
+// fn 返回错误时停止并返回该错误。
+func (r *Reader) Each(fn func(i int32, e Entry) error) error {
+	var prev int32 = -1
+	for i := int32(0); i < r.Count(); i++ {
+		e, err := r.Entry(i)
+		if err != nil {
+			return err
+		}
+		if e.Prefix <= prev {
+			return fmt.Errorf("index %d is not in ascending order", i)
+		}
+		if _, ok := r.offsets[e.RecordOffset]; !ok {
+			return fmt.Errorf("index %d points to invalid record offset %d", i, e.RecordOffset)
+		}
+		if err := fn(i, e); err != nil {
+			return err
+		}
+		prev = e.Prefix
+	}
+	return nil
+}
+
+// Validate 完整校验索引区，见 Each。New 只校验头部和记录区。
+func (r *Reader) Validate() error {
+	return r.Each(func(int32, Entry) error { return nil })
+}
+
+// Search 用二分法在索引区中查找号码前七位 prefix。
+func (r *Reader) Search(prefix int32) (e Entry, found bool, err error) {
+	left, right := int32(0), r.Count()-1
+	for left <= right {
+		mid := (left + right) / 2
+		e, err := r.Entry(mid)
+		if err != nil {
+			return Entry{}, false, err
+		}
+		switch {
+		case e.Prefix > prefix:
+			right = mid - 1
+		case e.Prefix < prefix:
+			left = mid + 1
+		default:
+			return e, true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// Find 查询纯数字的号码 number。7~11 位的号码按前七位查询归属地，
+// 13 位的号码按物联网号码查询，只返回运营商。number 不做任何整理，整理见 phonedata.Normalize。
+func (r *Reader) Find(number string) (*PhoneRecord, error) {
+	if len(number) == IoTPhoneLength {
+		return FindIoT(number, number, r.carriers)
+	}
+	if len(number) < 7 || len(number) > 11 {
+		return nil, lookupError(number, ErrInvalidLength)
+	}
+	prefix, ok := ParsePrefix(number)
+	if !ok {
+		return nil, lookupError(number, ErrInvalidNumber)
+	}
+	e, found, err := r.Search(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, lookupError(number, ErrNotFound)
+	}
+	rec, ok := r.Record(e.RecordOffset)
+	if !ok {
+		return nil, fmt.Errorf("index of %d points to invalid record offset %d", prefix, e.RecordOffset)
+	}
+	c := r.carriers.Of(e.CardType)
+	return &PhoneRecord{
+		PhoneNum: number,
+		Province: rec.Province,
+		City:     rec.City,
+		ZipCode:  rec.ZipCode,
+		AreaZone: rec.AreaZone,
+		CardType: c.Name,
+		Carrier:  c,
+		Type:     Mobile,
+	}, nil
+}
+
+// ParsePrefix 把号码的前七位解析为索引区中的号码前缀，号码不足七位或前七位含非数字字符时 ok 为 false。
+func ParsePrefix(number string) (prefix int32, ok bool) {
+	if len(number) < 7 {
+		return 0, false
+	}
+	for i := 0; i < 7; i++ {
+		d := number[i]
+		if d < '0' || d > '9' {
+			return 0, false
+		}
+		prefix = prefix*10 + int32(d-'0')
+	}
+	return prefix, true
+}
@@ -0,0 +1,150 @@
+package reader
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/xluohome/phonedata/carrier"
+)
+
+func loadPhoneData(t testing.TB) []byte {
+	content, err := ioutil.ReadFile("../phone.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestNew(t *testing.T) {
+	content := loadPhoneData(t)
+	rd, err := New(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rd.Version() != "2108" || rd.Size() != int32(len(content)) || rd.Count() != 454336 {
+		t.Fatal("验证失败", rd.Version(), rd.Size(), rd.Count())
+	}
+	if err := rd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	records := rd.Records()
+	if len(records) == 0 || records[0].Offset != HeadLength {
+		t.Fatal("验证失败", len(records))
+	}
+	if r, ok := rd.Record(records[1].Offset); !ok || *r != records[1] {
+		t.Fatal("验证失败", r)
+	}
+	if _, ok := rd.Record(records[1].Offset + 1); ok {
+		t.Fatal("验证失败")
+	}
+	if len(rd.Services()) == 0 || rd.Services()[0].Number == "" {
+		t.Fatal("验证失败", rd.Services())
+	}
+
+	first, err := rd.Entry(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, found, err := rd.Search(first.Prefix); err != nil || !found || e != first {
+		t.Fatal("验证失败", e, found, err)
+	}
+	if _, err := rd.Entry(rd.Count()); err == nil {
+		t.Fatal("应该返回错误")
+	}
+}
+
+func TestFind(t *testing.T) {
+	rd, err := New(loadPhoneData(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := rd.Find("18957509123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Province != "浙江" || pr.City != "绍兴" || pr.AreaZone != "0575" || pr.CardType != "中国电信" ||
+		pr.Carrier.ID != carrier.CTCC || pr.Type != Mobile {
+		t.Fatal("验证失败", pr)
+	}
+
+	pr, err = rd.Find("1440123456789")
+	if err != nil || pr.Type != IoT || pr.Carrier.ID != carrier.CMCC || pr.Province != "" {
+		t.Fatal("验证失败", pr, err)
+	}
+
+	for number, reason := range map[string]error{
+		"1420123456789": ErrNotFound,
+		"10648abcdefgh": ErrInvalidNumber,
+		"1300":          ErrInvalidLength,
+		"189575091234":  ErrInvalidLength,
+		"189-5750912":   ErrInvalidNumber,
+		"10074872323":   ErrNotFound,
+	} {
+		_, err := rd.Find(number)
+		var lookupErr *LookupError
+		if !errors.Is(err, reason) || !errors.As(err, &lookupErr) || lookupErr.Number != number {
+			t.Fatal(number, "错误的结果", err)
+		}
+	}
+}
+
+func TestNewReaderAt(t *testing.T) {
+	content := loadPhoneData(t)
+	rd, err := NewReaderAt(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := rd.Find("18957509123")
+	if err != nil || pr.City != "绍兴" {
+		t.Fatal("验证失败", pr, err)
+	}
+}
+
+func TestInvalidData(t *testing.T) {
+	record := "浙江|杭州|310000|0571\x00"
+	entry := func(prefix byte, offset byte) string {
+		return string([]byte{prefix, 0, 0, 0, offset, 0, 0, 0, 1})
+	}
+	header := func(indexOffset int) string {
+		return "2410" + string([]byte{byte(indexOffset), 0, 0, 0})
+	}
+	valid := header(8+len(record)) + record + entry(1, 8) + entry(2, 8)
+	rd, err := New([]byte(valid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rd.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range map[string]string{
+		"too short":      "2410",
+		"bad offset":     header(100) + record,
+		"bad length":     header(8+len(record)) + record + "\x01",
+		"not terminated": header(8+len(record)-1) + record[:len(record)-1],
+		"bad record":     header(8+len("a|b\x00")) + "a|b\x00",
+		"bad service":    header(8+len(record)+len("#service\x00x|y\x00")) + record + "#service\x00x|y\x00",
+	} {
+		if _, err := New([]byte(content)); err == nil {
+			t.Fatal(name, "应该返回错误")
+		}
+	}
+
+	for name, content := range map[string]string{
+		"not ascending":  header(8+len(record)) + record + entry(2, 8) + entry(1, 8),
+		"invalid record": header(8+len(record)) + record + entry(1, 9),
+	} {
+		rd, err := New([]byte(content))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if err := rd.Validate(); err == nil {
+			t.Fatal(name, "应该返回错误")
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package reader
+
+import (
+	"fmt"
+
+	"github.com/xluohome/phonedata/carrier"
+)
+
+// PhoneRecord 是号码查询的结果，phonedata.Find 和 phonedatatool 的查询都返回它。
+type PhoneRecord struct {
+	PhoneNum string
+	Province string
+	City     string
+	ZipCode  string
+	AreaZone string
+	CardType string          // 运营商中文名，即 Carrier.Name，固定电话为空
+	Carrier  carrier.Carrier // 结构化的运营商信息，固定电话为零值
+	Type     NumberType      // 号码类型，固定电话为 FixedLine
+}
+
+func (pr PhoneRecord) String() string {
+	return fmt.Sprintf("PhoneNum: %s\nAreaZone: %s\nCardType: %s\nCity: %s\nZipCode: %s\nProvince: %s\n", pr.PhoneNum, pr.AreaZone, pr.CardType, pr.City, pr.ZipCode, pr.Province)
+}
+
+// NumberType 是号码的类型。
+type NumberType int
+
+const (
+	Mobile        NumberType = iota // 手机号码
+	FixedLine                       // 固定电话
+	Service                         // 短号码、服务号码，如 10086、95588、12345、400/800
+	IoT                             // 13 位物联网号码
+	International                   // 国外号码
+	Invalid                         // 无效号码
+)
+
+func (t NumberType) String() string {
+	switch t {
+	case Mobile:
+		return "mobile"
+	case FixedLine:
+		return "fixed-line"
+	case Service:
+		return "service"
+	case IoT:
+		return "iot"
+	case International:
+		return "international"
+	case Invalid:
+		return "invalid"
+	default:
+		return fmt.Sprintf("NumberType(%d)", int(t))
+	}
+}
+
+// Record 是记录区中的一条记录。
+type Record struct {
+	Offset   int32 // 在文件中的偏移，即索引引用它的值
+	Province string
+	City     string
+	ZipCode  string
+	AreaZone string
+}
+
+// ServiceRecord 是服务号码表中的一条记录，如 10086 中国移动客服。
+type ServiceRecord struct {
+	Number   string
+	Name     string
+	Category string // 类别，如 运营商、银行、政务、紧急
+}
+
+func (sr ServiceRecord) String() string {
+	return sr.Number + " " + sr.Name + " " + sr.Category
+}
+
+// Entry 是索引区中的一条索引。
+type Entry struct {
+	Prefix       int32 // 号码前七位
+	RecordOffset int32 // 记录区偏移
+	CardType     byte  // 卡类型
+}
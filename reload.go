@@ -0,0 +1,77 @@
+package phonedata
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+var errNoPath = errors.New("phone data not opened from a file, can't reload")
+
+// Reload 重新读取 Open 时指定的数据文件。新文件完整校验通过后才会原子地替换当前数据，
+// 正在进行的查询仍使用旧数据完成；校验失败时继续使用旧数据。
+// 返回替换前后的版本号。
+//
+// 以 StorageMmap 或 StorageFile 打开时，数据文件必须用 os.Rename 整体替换，不能原地改写，见 WithStorage。
+func (db *DB) Reload() (oldVersion, newVersion string, err error) {
+	if db.path == "" {
+		return "", "", errNoPath
+	}
+	db.reloadMu.Lock()
+	defer db.reloadMu.Unlock()
+
+	oldVersion = db.Version()
+	s, err := loadSnapshot(db.path, db.opts)
+	if err != nil {
+		return oldVersion, oldVersion, err
+	}
+	db.snap.Store(s)
+	return oldVersion, s.version(), nil
+}
+
+// Watch 每隔 interval 检查一次数据文件的修改时间和大小，发生变化时调用 Reload，
+// 并把结果传给 notify（notify 可以为 nil）。以 StorageMmap 打开时还检查映射的文件本身的大小，
+// 文件被原地改写时即使路径的修改时间没有变化也会 Reload。调用返回的 stop 函数停止检查。
+// 与 Reload 相同，不是通过 Open 打开的 DB 返回错误。
+func (db *DB) Watch(interval time.Duration, notify func(oldVersion, newVersion string, err error)) (stop func(), err error) {
+	if db.path == "" {
+		return nil, errNoPath
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		last, _ := os.Stat(db.path)
+		var rewrittenMapping *mmap
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			fi, err := os.Stat(db.path)
+			if err != nil {
+				continue
+			}
+			// 同一个映射被原地改写只 Reload 一次，失败后等路径上的文件再次变化
+			m := db.snapshot().mapping
+			rewritten := m != nil && m != rewrittenMapping && m.changed()
+			if !rewritten && last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size() {
+				continue
+			}
+			if rewritten {
+				rewrittenMapping = m
+			}
+			last = fi
+			oldVersion, newVersion, err := db.Reload()
+			if notify != nil {
+				notify(oldVersion, newVersion, err)
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}, nil
+}
@@ -0,0 +1,140 @@
+package phonedata
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func writePhoneData(t *testing.T, file string, version string) {
+	content, err := ioutil.ReadFile(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(content, version)
+	if err := ioutil.WriteFile(file, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReload(t *testing.T) {
+	file := path.Join(t.TempDir(), PHONE_DAT)
+	writePhoneData(t, file, "2108")
+	db, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writePhoneData(t, file, "2109")
+	oldVersion, newVersion, err := db.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldVersion != "2108" || newVersion != "2109" || db.Version() != "2109" {
+		t.Fatal("验证失败", oldVersion, newVersion, db.Version())
+	}
+
+	// 损坏的文件不会替换当前数据
+	if err := ioutil.WriteFile(file, []byte("2110\x08\x00\x00\x00broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := db.Reload(); err == nil {
+		t.Fatal("错误的结果")
+	}
+	if db.Version() != "2109" {
+		t.Fatal("验证失败", db.Version())
+	}
+	if _, err := db.Find("18957509123"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadMmap(t *testing.T) {
+	dir := t.TempDir()
+	file := path.Join(dir, PHONE_DAT)
+	writePhoneData(t, file, "2108")
+	db, err := Open(file, WithStorage(StorageMmap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// 用 rename 替换文件，旧的映射仍然可用
+	writePhoneData(t, file+".tmp", "2109")
+	if err := os.Rename(file+".tmp", file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Find("18957509123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, newVersion, err := db.Reload(); err != nil || newVersion != "2109" {
+		t.Fatal("验证失败", newVersion, err)
+	}
+	if _, err := db.Find("18957509123"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 原地截短文件可以被发现，此后不能再访问映射
+	if db.snapshot().mapping.changed() {
+		t.Fatal("错误的结果")
+	}
+	if err := ioutil.WriteFile(file, []byte("2110"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !db.snapshot().mapping.changed() {
+		t.Fatal("验证失败")
+	}
+	if db.Version() != "2109" {
+		t.Fatal("验证失败", db.Version())
+	}
+}
+
+func TestReloadWithoutFile(t *testing.T) {
+	content, err := ioutil.ReadFile(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Load(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := db.Reload(); err == nil {
+		t.Fatal("错误的结果")
+	}
+	if stop, err := db.Watch(time.Millisecond, nil); err == nil || stop != nil {
+		t.Fatal("错误的结果")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	file := path.Join(t.TempDir(), PHONE_DAT)
+	writePhoneData(t, file, "2108")
+	db, err := Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan string, 1)
+	stop, err := db.Watch(10*time.Millisecond, func(oldVersion, newVersion string, err error) {
+		if err == nil {
+			reloaded <- oldVersion + "->" + newVersion
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	time.Sleep(20 * time.Millisecond)
+	writePhoneData(t, file, "2109")
+	select {
+	case s := <-reloaded:
+		if s != "2108->2109" {
+			t.Fatal("验证失败", s)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+}
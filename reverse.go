@@ -0,0 +1,245 @@
+package phonedata
+
+import (
+	"runtime"
+	"sort"
+
+	"github.com/xluohome/phonedata/reader"
+)
+
+// PrefixRange 是一段连续的号码前七位，First 和 Last 都包含在内。
+type PrefixRange struct {
+	First int32
+	Last  int32
+}
+
+// Count 返回号段数。
+func (r PrefixRange) Count() int32 {
+	return r.Last - r.First + 1
+}
+
+// RegionQuery 是反查号段的条件，零值字段表示不限。
+type RegionQuery struct {
+	Province string
+	City     string
+	CardType byte // 卡类型，如 CMCC
+}
+
+// segment_range 是同一条记录、同一卡类型下的一段连续号段。
+type segment_range struct {
+	PrefixRange
+	card_type byte
+}
+
+// region_index 是反查号段和查询固定电话使用的索引。只查询手机号码时用不到，
+// 为了不增加加载时间和内存，第一次使用时才建立，见 snapshot.regionIndex。
+type region_index struct {
+	rd *reader.Reader
+
+	ranges           map[int32][]segment_range // 记录区偏移 -> 该记录的号段
+	record_offsets   []int32                   // 所有记录区偏移，升序
+	province_records map[string][]int32        // 省名 -> 记录区偏移
+	city_records     map[string][]int32        // 城市名 -> 记录区偏移
+	area_records     map[string][]int32        // 长途区号 -> 记录区偏移
+	area_primary     map[string]int32          // 长途区号 -> 号段最多的记录区偏移
+	zip_records      map[string][]int32        // 邮政编码 -> 记录区偏移
+}
+
+// regionIndex 返回反查索引，第一次调用时遍历索引区建立。
+// 读取数据文件失败时不保存错误，下次调用时重新建立。
+func (s *snapshot) regionIndex() (*region_index, error) {
+	s.region_mu.Lock()
+	defer s.region_mu.Unlock()
+	if s.region != nil {
+		return s.region, nil
+	}
+	idx, err := newRegionIndex(s)
+	if err != nil {
+		return nil, err
+	}
+	s.region = idx
+	return idx, nil
+}
+
+func newRegionIndex(s *snapshot) (*region_index, error) {
+	defer runtime.KeepAlive(s)
+	idx := &region_index{
+		rd:     s.rd,
+		ranges: make(map[int32][]segment_range),
+	}
+	if err := s.rd.Each(func(i int32, e reader.Entry) error {
+		idx.addRange(e.RecordOffset, e.CardType, e.Prefix)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	idx.buildRegionIndex()
+	idx.buildAreaIndex()
+	return idx, nil
+}
+
+// addRange 按号码升序调用，把号段 phone 合并进记录 record_offset 的号段列表。
+func (idx *region_index) addRange(record_offset int32, card_type byte, phone int32) {
+	ranges := idx.ranges[record_offset]
+	if n := len(ranges); n > 0 && ranges[n-1].Last == phone-1 && ranges[n-1].card_type == card_type {
+		ranges[n-1].Last = phone
+		return
+	}
+	idx.ranges[record_offset] = append(ranges, segment_range{PrefixRange{phone, phone}, card_type})
+}
+
+// Region 是记录区中的一个地区及其全部号段。
+type Region struct {
+	Province string
+	City     string
+	ZipCode  string
+	AreaZone string
+	Ranges   []PrefixRange // 按升序排列，相邻的号段已合并
+}
+
+// PrefixCount 返回地区的号段数。
+func (r Region) PrefixCount() int32 {
+	var count int32
+	for _, pr := range r.Ranges {
+		count += pr.Count()
+	}
+	return count
+}
+
+// buildRegionIndex 建立省、市、区号、邮编到记录的二级索引，列表按记录在文件中的顺序排列。
+func (idx *region_index) buildRegionIndex() {
+	records := idx.rd.Records()
+	idx.record_offsets = make([]int32, 0, len(records))
+	idx.province_records = make(map[string][]int32)
+	idx.city_records = make(map[string][]int32)
+	idx.area_records = make(map[string][]int32)
+	idx.zip_records = make(map[string][]int32)
+	for _, r := range records {
+		idx.record_offsets = append(idx.record_offsets, r.Offset)
+		idx.province_records[r.Province] = append(idx.province_records[r.Province], r.Offset)
+		idx.city_records[r.City] = append(idx.city_records[r.City], r.Offset)
+		idx.area_records[r.AreaZone] = append(idx.area_records[r.AreaZone], r.Offset)
+		idx.zip_records[r.ZipCode] = append(idx.zip_records[r.ZipCode], r.Offset)
+	}
+}
+
+// regions 返回记录对应的地区，没有号段的记录不返回。
+func (idx *region_index) regions(record_offsets []int32) []Region {
+	var regions []Region
+	for _, record_offset := range record_offsets {
+		ranges := idx.rangesOf([]int32{record_offset}, 0)
+		if len(ranges) == 0 {
+			continue
+		}
+		r, _ := idx.rd.Record(record_offset)
+		regions = append(regions, Region{
+			Province: r.Province,
+			City:     r.City,
+			ZipCode:  r.ZipCode,
+			AreaZone: r.AreaZone,
+			Ranges:   ranges,
+		})
+	}
+	return regions
+}
+
+// matchRecords 返回符合省、市条件的记录偏移。
+func (idx *region_index) matchRecords(q RegionQuery) []int32 {
+	var candidates []int32
+	switch {
+	case q.City != "":
+		candidates = idx.city_records[q.City]
+	case q.Province != "":
+		candidates = idx.province_records[q.Province]
+	default:
+		candidates = idx.record_offsets
+	}
+	var matched []int32
+	for _, record_offset := range candidates {
+		if r, _ := idx.rd.Record(record_offset); q.Province == "" || r.Province == q.Province {
+			matched = append(matched, record_offset)
+		}
+	}
+	return matched
+}
+
+// rangesOf 返回记录中符合卡类型条件的号段，已合并相邻的号段并按升序排列。
+func (idx *region_index) rangesOf(record_offsets []int32, card_type byte) []PrefixRange {
+	var result []PrefixRange
+	for _, record_offset := range record_offsets {
+		for _, r := range idx.ranges[record_offset] {
+			if card_type == 0 || r.card_type == card_type {
+				result = append(result, r.PrefixRange)
+			}
+		}
+	}
+	return mergeRanges(result)
+}
+
+func mergeRanges(ranges []PrefixRange) []PrefixRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].First < ranges[j].First
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		if last := &merged[len(merged)-1]; r.First <= last.Last+1 {
+			if r.Last > last.Last {
+				last.Last = r.Last
+			}
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// PrefixRanges 返回符合条件的所有号段，相邻的号段合并成一段，按升序排列。
+// 例如 RegionQuery{Province: "浙江", City: "绍兴", CardType: CTCC} 返回绍兴电信的号段。
+// 第一次反查时遍历索引区建立反查索引，读取数据文件失败时返回错误；没有符合条件的号段时返回空和 nil。
+func (db *DB) PrefixRanges(q RegionQuery) ([]PrefixRange, error) {
+	idx, err := db.snapshot().regionIndex()
+	if err != nil {
+		return nil, err
+	}
+	return idx.rangesOf(idx.matchRecords(q), q.CardType), nil
+}
+
+// Prefixes 返回符合条件的所有号码前七位，按升序排列。
+func (db *DB) Prefixes(q RegionQuery) ([]int32, error) {
+	ranges, err := db.PrefixRanges(q)
+	if err != nil {
+		return nil, err
+	}
+	var prefixes []int32
+	for _, r := range ranges {
+		for prefix := r.First; prefix <= r.Last; prefix++ {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes, nil
+}
+
+// ByAreaCode 返回长途区号为 code 的所有地区及其号段，code 可以省略开头的 0，如 "0575" 或 "575"。
+// 同一区号可能对应多个城市，例如 0551 对应合肥和巢湖。
+func (db *DB) ByAreaCode(code string) ([]Region, error) {
+	if code != "" && code[0] != '0' {
+		code = "0" + code
+	}
+	idx, err := db.snapshot().regionIndex()
+	if err != nil {
+		return nil, err
+	}
+	return idx.regions(idx.area_records[code]), nil
+}
+
+// ByZipCode 返回邮政编码为 zip 的所有地区及其长途区号和号段，可以用 Region.PrefixCount 统计号段数。
+func (db *DB) ByZipCode(zip string) ([]Region, error) {
+	idx, err := db.snapshot().regionIndex()
+	if err != nil {
+		return nil, err
+	}
+	return idx.regions(idx.zip_records[zip]), nil
+}
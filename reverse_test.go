@@ -0,0 +1,158 @@
+package phonedata
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrefixRanges(t *testing.T) {
+	db, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []RegionQuery{
+		{City: "绍兴"},
+		{Province: "浙江", City: "绍兴", CardType: CTCC},
+		{Province: "浙江"},
+		{Province: "新疆", CardType: CUCC},
+	} {
+		ranges, err := db.PrefixRanges(q)
+		if err != nil || len(ranges) == 0 {
+			t.Fatal(q, "验证失败", err)
+		}
+		prefixes, err := db.Prefixes(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var count int32
+		for i, r := range ranges {
+			if r.First > r.Last || (i > 0 && ranges[i-1].Last+1 >= r.First) {
+				t.Fatal(q, "号段没有合并或排序", ranges[i-1], r)
+			}
+			count += r.Count()
+		}
+		if int(count) != len(prefixes) {
+			t.Fatal(q, "验证失败", count, len(prefixes))
+		}
+		// 反查出的每个号段都能正向查到同样的省、市、卡类型
+		for i := 0; i < len(prefixes); i += 13 {
+			loc, err := db.Lookup(fmt.Sprint(prefixes[i]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (q.Province != "" && loc.Province != q.Province) || (q.City != "" && loc.City != q.City) ||
+				(q.CardType != 0 && loc.CardType != CardTypemap[q.CardType]) {
+				t.Fatal(q, prefixes[i], "验证失败", loc)
+			}
+		}
+	}
+
+	if ranges, err := db.PrefixRanges(RegionQuery{Province: "江苏", City: "绍兴"}); err != nil || len(ranges) != 0 {
+		t.Fatal("错误的结果", ranges, err)
+	}
+}
+
+func TestRegionIndexLazy(t *testing.T) {
+	db, err := Open(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 只查询手机号码时不建立反查索引
+	if _, err := db.Find("18957509123"); err != nil {
+		t.Fatal(err)
+	}
+	if db.snapshot().region != nil {
+		t.Fatal("反查索引不应在加载时建立")
+	}
+	if _, err := db.FindLandline("0575-88888888"); err != nil {
+		t.Fatal(err)
+	}
+	if db.snapshot().region == nil {
+		t.Fatal("验证失败")
+	}
+}
+
+func TestRegionIndexError(t *testing.T) {
+	db, err := Open(PHONE_DAT, WithStorage(StorageFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 关闭文件后建立反查索引时读取索引区失败，错误返回给调用方，不当作没有结果
+	db.Close()
+	if ranges, err := db.PrefixRanges(RegionQuery{City: "绍兴"}); err == nil {
+		t.Fatal("验证失败", ranges)
+	}
+	if _, err := db.ByAreaCode("0575"); err == nil {
+		t.Fatal("验证失败")
+	}
+	if _, err := db.ByZipCode("312000"); err == nil {
+		t.Fatal("验证失败")
+	}
+	if db.snapshot().region != nil {
+		t.Fatal("失败的结果不应保存")
+	}
+}
+
+func TestMergeRanges(t *testing.T) {
+	merged := mergeRanges([]PrefixRange{{10, 12}, {1, 3}, {4, 5}, {11, 20}, {30, 30}})
+	if fmt.Sprint(merged) != "[{1 5} {10 20} {30 30}]" {
+		t.Fatal("验证失败", merged)
+	}
+}
+
+func TestByAreaCode(t *testing.T) {
+	db, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	regions, err := db.ByAreaCode("0551")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(regions) != 2 || regions[0].City != "巢湖" || regions[1].City != "合肥" {
+		t.Fatal("验证失败", regions)
+	}
+	for _, r := range regions {
+		if r.Province != "安徽" || r.AreaZone != "0551" || r.PrefixCount() == 0 {
+			t.Fatal("验证失败", r)
+		}
+		if ranges, err := db.PrefixRanges(RegionQuery{Province: r.Province, City: r.City}); err != nil || fmt.Sprint(r.Ranges) != fmt.Sprint(ranges) {
+			t.Fatal("验证失败", r, err)
+		}
+	}
+
+	if regions, err := db.ByAreaCode("575"); err != nil || len(regions) != 1 || regions[0].City != "绍兴" {
+		t.Fatal("验证失败", regions, err)
+	}
+	if regions, err := db.ByAreaCode("0000"); err != nil || len(regions) != 0 {
+		t.Fatal("错误的结果", regions, err)
+	}
+}
+
+func TestByZipCode(t *testing.T) {
+	db, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	regions, err := db.ByZipCode("312000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(regions) != 1 || regions[0].City != "绍兴" || regions[0].AreaZone != "0575" || regions[0].PrefixCount() == 0 {
+		t.Fatal("验证失败", regions)
+	}
+
+	regions, err = db.ByZipCode("137400")
+	if err != nil || len(regions) == 0 {
+		t.Fatal("验证失败", regions, err)
+	}
+	for _, r := range regions {
+		if r.Province != "内蒙古" || r.ZipCode != "137400" || r.AreaZone != "0482" || r.PrefixCount() == 0 {
+			t.Fatal("验证失败", r)
+		}
+	}
+
+	if regions, err := db.ByZipCode("000000"); err != nil || len(regions) != 0 {
+		t.Fatal("错误的结果", regions, err)
+	}
+}
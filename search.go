@@ -0,0 +1,88 @@
+package phonedata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	searchMu    sync.Mutex
+	searchPaths []string
+)
+
+// SearchError 表示在所有查找位置都没有找到数据文件。
+type SearchError struct {
+	Name  string   // 数据文件名
+	Tried []string // 按顺序尝试过的路径
+}
+
+func (e *SearchError) Error() string {
+	return fmt.Sprintf("%s not found, tried: %s", e.Name, strings.Join(e.Tried, ", "))
+}
+
+// AddSearchPath 把目录 dir 加入默认数据文件的查找位置，排在内置位置之后。
+// 默认 DB 加载成功之后再加入的目录不起作用。
+func AddSearchPath(dir string) {
+	searchMu.Lock()
+	defer searchMu.Unlock()
+	searchPaths = append(searchPaths, dir)
+}
+
+// resetSearchPaths 清除通过 AddSearchPath 加入的目录，供测试使用。
+func resetSearchPaths() {
+	searchMu.Lock()
+	defer searchMu.Unlock()
+	searchPaths = nil
+}
+
+// SearchPaths 按顺序返回查找默认数据文件的目录：
+//  1. 环境变量 PHONE_DATA_DIR（如果设置了）
+//  2. 可执行文件所在目录
+//  3. $XDG_DATA_HOME/phonedata（未设置时为 ~/.local/share/phonedata）
+//  4. /usr/share/phonedata
+//  5. 当前工作目录
+//  6. 通过 AddSearchPath 加入的目录
+//  7. 本包源码所在目录（仅在源码所在的机器上有效）
+func SearchPaths() []string {
+	var dirs []string
+	if dir := os.Getenv("PHONE_DATA_DIR"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if exe, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Dir(exe))
+	}
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		dirs = append(dirs, filepath.Join(dir, "phonedata"))
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".local", "share", "phonedata"))
+	}
+	dirs = append(dirs, "/usr/share/phonedata")
+	if wd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, wd)
+	}
+	searchMu.Lock()
+	dirs = append(dirs, searchPaths...)
+	searchMu.Unlock()
+	if _, fulleFilename, _, ok := runtime.Caller(0); ok {
+		dirs = append(dirs, filepath.Dir(fulleFilename))
+	}
+	return dirs
+}
+
+// FindDataFile 在 SearchPaths 中依次查找 phone.dat，返回第一个存在的文件路径。
+// 都找不到时返回 *SearchError。
+func FindDataFile() (string, error) {
+	e := &SearchError{Name: PHONE_DAT}
+	for _, dir := range SearchPaths() {
+		file := filepath.Join(dir, PHONE_DAT)
+		if fi, err := os.Stat(file); err == nil && fi.Mode().IsRegular() {
+			return file, nil
+		}
+		e.Tried = append(e.Tried, file)
+	}
+	return "", e
+}
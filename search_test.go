@@ -0,0 +1,105 @@
+package phonedata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setenv(t *testing.T, key, value string) {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestSearchPaths(t *testing.T) {
+	dir := t.TempDir()
+	setenv(t, "PHONE_DATA_DIR", dir)
+	setenv(t, "XDG_DATA_HOME", "/xdg")
+	AddSearchPath("/added")
+	t.Cleanup(resetSearchPaths)
+
+	dirs := SearchPaths()
+	if dirs[0] != dir {
+		t.Fatal("验证失败", dirs)
+	}
+	xdg, usr, added := -1, -1, -1
+	for i, d := range dirs {
+		switch d {
+		case filepath.Join("/xdg", "phonedata"):
+			xdg = i
+		case "/usr/share/phonedata":
+			usr = i
+		case "/added":
+			added = i
+		}
+	}
+	if !(0 < xdg && xdg < usr && usr < added && added < len(dirs)-1) {
+		t.Fatal("验证失败", dirs)
+	}
+
+	// PHONE_DATA_DIR 下没有数据文件时，最后回退到源码所在目录
+	file, err := FindDataFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(file) != dirs[len(dirs)-1] {
+		t.Fatal("验证失败", file)
+	}
+
+	content, err := ioutil.ReadFile(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, PHONE_DAT), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if file, err = FindDataFile(); err != nil || file != filepath.Join(dir, PHONE_DAT) {
+		t.Fatal("验证失败", file, err)
+	}
+}
+
+func TestDefaultRetry(t *testing.T) {
+	old, _ := Default()
+	t.Cleanup(func() { SetDefault(old) })
+
+	// 第一次加载失败的结果不会被保留
+	dir := t.TempDir()
+	setenv(t, "PHONE_DATA_DIR", dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, PHONE_DAT), []byte("broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	SetDefault(nil)
+	if _, err := Default(); err == nil {
+		t.Fatal("错误的结果")
+	}
+	if _, err := Find("18957509123"); err == nil {
+		t.Fatal("错误的结果")
+	}
+
+	content, err := ioutil.ReadFile(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, PHONE_DAT), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if pr, err := Find("18957509123"); err != nil || pr.City != "绍兴" {
+		t.Fatal("验证失败", pr, err)
+	}
+}
+
+func TestSearchError(t *testing.T) {
+	err := &SearchError{Name: PHONE_DAT, Tried: []string{"/a/phone.dat", "/b/phone.dat"}}
+	if !strings.Contains(err.Error(), "/a/phone.dat, /b/phone.dat") {
+		t.Fatal("验证失败", err)
+	}
+}
@@ -0,0 +1,37 @@
+package phonedata
+
+import (
+	"github.com/xluohome/phonedata/reader"
+)
+
+// SERVICE_MARKER 是记录区中服务号码表的起始标记，见 reader.ServiceMarker。
+const SERVICE_MARKER = reader.ServiceMarker
+
+// ServiceRecord 是服务号码表中的一条记录，如 10086 中国移动客服。
+type ServiceRecord = reader.ServiceRecord
+
+func (s *snapshot) findService(number string) (*ServiceRecord, error) {
+	national, err := Normalize(number)
+	if err != nil {
+		return nil, err
+	}
+	if sr, ok := s.services[national]; ok {
+		return sr, nil
+	}
+	return nil, lookupError(number, ErrNotFound)
+}
+
+// FindService 在服务号码表中查询短号码、服务号码，如 10086、95588、12345。
+// 返回的 *ServiceRecord 由所有查询共享，调用方不能修改。
+func (db *DB) FindService(number string) (*ServiceRecord, error) {
+	return db.snapshot().findService(number)
+}
+
+// FindService 在默认 DB 中查询服务号码，见 (*DB).FindService。
+func FindService(number string) (*ServiceRecord, error) {
+	db, err := Default()
+	if err != nil {
+		return nil, err
+	}
+	return db.FindService(number)
+}
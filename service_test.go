@@ -0,0 +1,53 @@
+package phonedata
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFindService(t *testing.T) {
+	for number, expected := range map[string][2]string{
+		"10086":     {"中国移动客服", "运营商"},
+		"95588":     {"中国工商银行", "银行"},
+		"12345":     {"政务服务便民热线", "政务"},
+		"１２０":       {"急救电话", "紧急"},
+		"95-598":    {"国家电网", "公共事业"},
+		"+86 12306": {"中国铁路客服", "交通"},
+	} {
+		sr, err := FindService(number)
+		if err != nil {
+			t.Fatal(number, err)
+		}
+		if sr.Name != expected[0] || sr.Category != expected[1] {
+			t.Fatal(number, "验证失败", sr)
+		}
+	}
+
+	if _, err := FindService("95001"); !errors.Is(err, ErrNotFound) {
+		t.Fatal("错误的结果", err)
+	}
+	if _, err := FindService("abc"); !errors.Is(err, ErrInvalidNumber) {
+		t.Fatal("错误的结果", err)
+	}
+
+	c := Classify("10086")
+	if c.Type != Service || c.Service == nil || c.Service.Name != "中国移动客服" {
+		t.Fatal("验证失败", c)
+	}
+}
+
+func TestLoadWithoutService(t *testing.T) {
+	db, err := Load([]byte("2108\x10\x00\x00\x00a|b|c|d\x00\x40\x42\x0f\x00\x08\x00\x00\x00\x01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.FindService("10086"); !errors.Is(err, ErrNotFound) {
+		t.Fatal("错误的结果", err)
+	}
+	if _, err := Load([]byte("2108\x19\x00\x00\x00a|b|c|d\x00#service\x00")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load([]byte("2108\x1c\x00\x00\x00a|b|c|d\x00#service\x00a|b\x00")); err == nil {
+		t.Fatal("错误的结果")
+	}
+}
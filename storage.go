@@ -0,0 +1,214 @@
+package phonedata
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/xluohome/phonedata/carrier"
+)
+
+// Storage 决定 DB 如何访问数据文件的内容。
+type Storage int
+
+const (
+	StorageMemory Storage = iota // 整个文件读入内存（默认）
+	StorageMmap                  // 只读映射文件，多个进程共享操作系统的页缓存
+	StorageFile                  // 通过 io.ReaderAt 读取文件，只在内存中保留少量页
+)
+
+const (
+	PAGE_SIZE          = 4096
+	DEFAULT_PAGE_COUNT = 64
+)
+
+func (s Storage) String() string {
+	switch s {
+	case StorageMemory:
+		return "memory"
+	case StorageMmap:
+		return "mmap"
+	case StorageFile:
+		return "file"
+	default:
+		return fmt.Sprintf("Storage(%d)", int(s))
+	}
+}
+
+// Option 是打开 DB 时的可选配置。
+type Option func(*options)
+
+type options struct {
+	storage    Storage
+	page_count int
+	dense      bool
+	carriers   *carrier.Registry
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		storage:    StorageMemory,
+		page_count: DEFAULT_PAGE_COUNT,
+		carriers:   carrier.Default(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithStorage 指定数据文件的访问方式，默认为 StorageMemory。
+//
+// StorageMmap 和 StorageFile 在查询时仍读取打开的文件，所以更新数据文件时必须先写入新文件，
+// 再用 os.Rename 替换，不能用 os.WriteFile 等方式原地改写：原地改写时 StorageFile 的一次查询
+// 可能混合读到新旧两份内容；StorageMmap 在文件被截短后访问映射会使进程因 SIGBUS 退出。
+// Watch 会检查映射的文件大小，发现被原地改写时立即 Reload，但不能避免在此之前的查询出错。
+func WithStorage(storage Storage) Option {
+	return func(o *options) {
+		o.storage = storage
+	}
+}
+
+// WithPageCache 指定 StorageFile 以及 LoadReaderAt 使用的页缓存页数，每页 PAGE_SIZE 字节。
+func WithPageCache(pages int) Option {
+	return func(o *options) {
+		if pages > 0 {
+			o.page_count = pages
+		}
+	}
+}
+
+// WithCarriers 指定解码卡类型使用的运营商注册表，默认为 carrier.Default()。
+// 数据文件中使用了自定义卡类型时，用 carrier.Default().LoadFile 得到包含这些卡类型的注册表。
+func WithCarriers(carriers *carrier.Registry) Option {
+	return func(o *options) {
+		if carriers != nil {
+			o.carriers = carriers
+		}
+	}
+}
+
+// WithDenseIndex 在加载时额外建立以号码前七位为下标的直接索引表，查询时不再二分查找。
+// 直接索引表约占 2 MB 内存，适合对查询延迟要求高的部署。
+func WithDenseIndex() Option {
+	return func(o *options) {
+		o.dense = true
+	}
+}
+
+// openStorage 按 o.storage 打开 path，返回可直接寻址的内容（StorageMemory、StorageMmap）
+// 或页缓存（StorageFile），以及需要在不再使用时关闭的资源。
+func openStorage(path string, o options) (content []byte, cache *pageCache, closer io.Closer, err error) {
+	switch o.storage {
+	case StorageMemory:
+		content, err = ioutil.ReadFile(path)
+		return content, nil, nil, err
+	case StorageMmap:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		m, err := mmapFile(f)
+		if err != nil {
+			f.Close()
+			return nil, nil, nil, err
+		}
+		return m.data, nil, m, nil
+	case StorageFile:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, nil, nil, err
+		}
+		return nil, newPageCache(f, fi.Size(), o.page_count), f, nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown storage %v", o.storage)
+	}
+}
+
+// pageCache 以 PAGE_SIZE 为单位缓存 io.ReaderAt 的内容，按最近最少使用淘汰。
+type pageCache struct {
+	r        io.ReaderAt
+	size     int64
+	capacity int
+
+	mu    sync.Mutex
+	pages map[int64]*list.Element
+	lru   *list.List
+}
+
+type page struct {
+	n   int64
+	buf []byte
+}
+
+func newPageCache(r io.ReaderAt, size int64, capacity int) *pageCache {
+	return &pageCache{
+		r:        r,
+		size:     size,
+		capacity: capacity,
+		pages:    make(map[int64]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// ReadAt 实现 io.ReaderAt。
+func (c *pageCache) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset %d", off)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for n < len(p) {
+		if off >= c.size {
+			return n, io.EOF
+		}
+		pg, err := c.page(off / PAGE_SIZE)
+		if err != nil {
+			return n, err
+		}
+		m := copy(p[n:], pg.buf[off%PAGE_SIZE:])
+		n += m
+		off += int64(m)
+	}
+	return n, nil
+}
+
+func (c *pageCache) page(n int64) (*page, error) {
+	if e, ok := c.pages[n]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*page), nil
+	}
+	var pg *page
+	if c.lru.Len() >= c.capacity {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		pg = e.Value.(*page)
+		delete(c.pages, pg.n)
+	} else {
+		pg = &page{buf: make([]byte, PAGE_SIZE)}
+	}
+	size := c.size - n*PAGE_SIZE
+	if size > PAGE_SIZE {
+		size = PAGE_SIZE
+	}
+	pg.n = n
+	pg.buf = pg.buf[:PAGE_SIZE]
+	if m, err := c.r.ReadAt(pg.buf[:size], n*PAGE_SIZE); int64(m) < size {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	pg.buf = pg.buf[:size]
+	c.pages[n] = c.lru.PushFront(pg)
+	return pg, nil
+}
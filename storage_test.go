@@ -0,0 +1,88 @@
+package phonedata
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	memory, err := Open(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(PHONE_DAT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbs := map[string]*DB{}
+	for _, storage := range []Storage{StorageMmap, StorageFile} {
+		db, err := Open(PHONE_DAT, WithStorage(storage), WithPageCache(4))
+		if err != nil {
+			t.Fatal(storage, err)
+		}
+		defer db.Close()
+		dbs[storage.String()] = db
+	}
+	if dbs["reader"], err = LoadReaderAt(bytes.NewReader(content), int64(len(content)), WithPageCache(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, db := range dbs {
+		if db.Version() != memory.Version() || db.Count() != memory.Count() {
+			t.Fatal(name, "验证失败", db.Version(), db.Count())
+		}
+		for prefix := 1300000; prefix < 2000000; prefix += 997 {
+			number := fmt.Sprintf("%d1234", prefix)
+			expected, expectedErr := memory.Find(number)
+			pr, err := db.Find(number)
+			if fmt.Sprint(expectedErr) != fmt.Sprint(err) || fmt.Sprint(expected) != fmt.Sprint(pr) {
+				t.Fatal(name, number, "验证失败", pr, err)
+			}
+		}
+	}
+}
+
+func TestPageCache(t *testing.T) {
+	buf := make([]byte, PAGE_SIZE*3+100)
+	for i := range buf {
+		buf[i] = byte(i % 251)
+	}
+	cache := newPageCache(bytes.NewReader(buf), int64(len(buf)), 2)
+	for _, off := range []int64{0, PAGE_SIZE - 3, PAGE_SIZE * 2, PAGE_SIZE*3 + 90, 5} {
+		p := make([]byte, 10)
+		if _, err := cache.ReadAt(p, off); err != nil {
+			t.Fatal(off, err)
+		}
+		if !bytes.Equal(p, buf[off:off+10]) {
+			t.Fatal(off, "验证失败")
+		}
+	}
+	if cache.lru.Len() != 2 {
+		t.Fatal("页数错误", cache.lru.Len())
+	}
+	if _, err := cache.ReadAt(make([]byte, 20), PAGE_SIZE*3+90); err == nil {
+		t.Fatal("错误的结果")
+	}
+}
+
+func TestStorageFileNoAlloc(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops objects under the race detector")
+	}
+	db, err := Open(PHONE_DAT, WithStorage(StorageFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	allocs := testing.AllocsPerRun(1000, func() {
+		if _, err := db.Lookup("18957509123"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatal("验证失败", allocs)
+	}
+}